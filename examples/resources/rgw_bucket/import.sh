@@ -0,0 +1,5 @@
+# import a bucket without tenant
+terraform import rgw_bucket.example example
+
+# import a bucket of a tenant
+terraform import rgw_bucket.example tenant/example
@@ -0,0 +1,5 @@
+# import the acl of a bucket without tenant
+terraform import rgw_bucket_acl.example example
+
+# import the acl of a bucket of a tenant
+terraform import rgw_bucket_acl.example tenant/example
@@ -0,0 +1,28 @@
+resource "rgw_bucket" "example" {
+  name = "example"
+}
+
+# grant read access to a user of a tenant and all authenticated users
+resource "rgw_bucket_acl" "example" {
+  bucket = rgw_bucket.example.name
+
+  grant {
+    id         = "tenant$reader"
+    permission = "READ"
+  }
+
+  grant {
+    uri        = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+    permission = "READ"
+  }
+}
+
+resource "rgw_bucket" "public" {
+  name = "public"
+}
+
+# allow anonymous read access
+resource "rgw_bucket_acl" "public" {
+  bucket = rgw_bucket.public.name
+  acl    = "public-read"
+}
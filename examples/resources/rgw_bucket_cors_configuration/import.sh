@@ -0,0 +1,5 @@
+# import the cors configuration of a bucket without tenant
+terraform import rgw_bucket_cors_configuration.example example
+
+# import the cors configuration of a bucket of a tenant
+terraform import rgw_bucket_cors_configuration.example tenant/example
@@ -0,0 +1,5 @@
+# import the lifecycle configuration of a bucket without tenant
+terraform import rgw_bucket_lifecycle_configuration.example example
+
+# import the lifecycle configuration of a bucket of a tenant
+terraform import rgw_bucket_lifecycle_configuration.example tenant/example
@@ -0,0 +1,5 @@
+# import the link of a bucket without tenant
+terraform import rgw_bucket_link.example example
+
+# import the link of a bucket of a tenant
+terraform import rgw_bucket_link.example tenant/example
@@ -0,0 +1,14 @@
+resource "rgw_user" "example" {
+  username     = "example"
+  tenant       = "example"
+  display_name = "Example"
+}
+
+# hand over an existing bucket to a user of another tenant, the bucket is
+# moved into the tenant and linked to its former owner again on destroy
+resource "rgw_bucket_link" "example" {
+  bucket = "legacy"
+  uid    = rgw_user.example.id
+
+  restore_previous_owner_on_destroy = true
+}
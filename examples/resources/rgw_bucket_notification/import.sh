@@ -0,0 +1,5 @@
+# import the notifications of a bucket without tenant
+terraform import rgw_bucket_notification.example example
+
+# import the notifications of a bucket of a tenant
+terraform import rgw_bucket_notification.example tenant/example
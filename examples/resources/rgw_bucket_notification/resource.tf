@@ -0,0 +1,20 @@
+resource "rgw_bucket" "example" {
+  name = "example"
+}
+
+resource "rgw_topic" "example" {
+  name          = "example"
+  push_endpoint = "http://hooks.example.com/uploads"
+}
+
+resource "rgw_bucket_notification" "example" {
+  bucket = rgw_bucket.example.name
+
+  topic {
+    id            = "images"
+    topic_arn     = rgw_topic.example.arn
+    events        = ["s3:ObjectCreated:*", "s3:ObjectRemoved:*"]
+    filter_prefix = "images/"
+    filter_suffix = ".jpg"
+  }
+}
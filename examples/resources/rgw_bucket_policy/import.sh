@@ -0,0 +1,5 @@
+# import the policy of a bucket without tenant
+terraform import rgw_bucket_policy.example example
+
+# import the policy of a bucket of a tenant
+terraform import rgw_bucket_policy.example tenant/example
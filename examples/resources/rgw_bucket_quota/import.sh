@@ -0,0 +1,5 @@
+# import the quota of a bucket without tenant
+terraform import rgw_bucket_quota.example example
+
+# import the quota of a bucket of a tenant
+terraform import rgw_bucket_quota.example tenant/example
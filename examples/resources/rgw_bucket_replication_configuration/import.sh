@@ -0,0 +1,5 @@
+# import the replication configuration of a bucket without tenant
+terraform import rgw_bucket_replication_configuration.example example
+
+# import the replication configuration of a bucket of a tenant
+terraform import rgw_bucket_replication_configuration.example tenant/example
@@ -0,0 +1,18 @@
+resource "rgw_bucket" "example" {
+  name = "example"
+}
+
+resource "rgw_bucket" "backup" {
+  name = "backup"
+}
+
+resource "rgw_bucket_replication_configuration" "example" {
+  bucket = rgw_bucket.example.name
+
+  rule {
+    status                    = "Enabled"
+    prefix                    = "logs/"
+    destination_bucket        = rgw_bucket.backup.name
+    delete_marker_replication = true
+  }
+}
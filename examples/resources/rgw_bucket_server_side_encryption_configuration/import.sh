@@ -0,0 +1,5 @@
+# import the encryption configuration of a bucket without tenant
+terraform import rgw_bucket_server_side_encryption_configuration.example example
+
+# import the encryption configuration of a bucket of a tenant
+terraform import rgw_bucket_server_side_encryption_configuration.example tenant/example
@@ -0,0 +1,9 @@
+resource "rgw_bucket" "example" {
+  name = "example"
+}
+
+resource "rgw_bucket_server_side_encryption_configuration" "example" {
+  bucket            = rgw_bucket.example.name
+  sse_algorithm     = "aws:kms"
+  kms_master_key_id = "example-key"
+}
@@ -0,0 +1,5 @@
+# import the website configuration of a bucket without tenant
+terraform import rgw_bucket_website_configuration.example example
+
+# import the website configuration of a bucket of a tenant
+terraform import rgw_bucket_website_configuration.example tenant/example
@@ -0,0 +1,18 @@
+resource "rgw_bucket" "example" {
+  name = "example"
+}
+
+resource "rgw_bucket_website_configuration" "example" {
+  bucket                = rgw_bucket.example.name
+  index_document_suffix = "index.html"
+  error_document_key    = "404.html"
+
+  routing_rule {
+    condition {
+      key_prefix_equals = "docs/"
+    }
+    redirect {
+      replace_key_prefix_with = "documents/"
+    }
+  }
+}
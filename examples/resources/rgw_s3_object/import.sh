@@ -0,0 +1,5 @@
+# import an object of a bucket without tenant
+terraform import rgw_s3_object.example example/robots.txt
+
+# import an object of a bucket of a tenant
+terraform import rgw_s3_object.example tenant:example/robots.txt
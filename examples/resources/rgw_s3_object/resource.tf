@@ -0,0 +1,27 @@
+resource "rgw_bucket" "example" {
+  name = "example"
+}
+
+resource "rgw_s3_object" "robots" {
+  bucket        = rgw_bucket.example.name
+  key           = "robots.txt"
+  content       = "User-agent: *\nDisallow: /\n"
+  content_type  = "text/plain"
+  cache_control = "max-age=3600"
+
+  metadata = {
+    owner = "sre"
+  }
+
+  tags = {
+    managed-by = "terraform"
+  }
+}
+
+# upload a file, files larger than multipart_threshold are uploaded in parts
+resource "rgw_s3_object" "config" {
+  bucket       = rgw_bucket.example.name
+  key          = "config/app.json"
+  source       = "${path.module}/app.json"
+  content_type = "application/json"
+}
@@ -0,0 +1,5 @@
+# import a subuser of a user without tenant
+terraform import rgw_subuser.example example:swift
+
+# import a subuser of a user of a tenant
+terraform import 'rgw_subuser.example' 'tenant$example:swift'
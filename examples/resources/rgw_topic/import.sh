@@ -0,0 +1,5 @@
+# import a topic by its arn
+terraform import rgw_topic.example arn:aws:sns:default::example
+
+# import a topic of a tenant
+terraform import rgw_topic.example arn:aws:sns:default:tenant:example
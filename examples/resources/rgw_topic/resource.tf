@@ -0,0 +1,11 @@
+resource "rgw_topic" "example" {
+  name          = "example"
+  push_endpoint = "amqp://rabbitmq.example.com:5672"
+  persistent    = true
+  opaque_data   = "team=infra"
+
+  push_endpoint_attributes = {
+    amqp-exchange  = "notifications"
+    amqp-ack-level = "broker"
+  }
+}
@@ -0,0 +1,5 @@
+# import a user without tenant
+terraform import rgw_user.example example
+
+# import a user of a tenant
+terraform import 'rgw_user.example' 'tenant$example'
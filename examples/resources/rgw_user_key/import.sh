@@ -0,0 +1,5 @@
+# import a key of a user without tenant
+terraform import rgw_user_key.example example/ACCESSKEY
+
+# import a key of a user of a tenant
+terraform import 'rgw_user_key.example' 'tenant$example/ACCESSKEY'
@@ -0,0 +1,29 @@
+resource "rgw_user" "example" {
+  username                 = "example"
+  display_name             = "Example"
+  exclusive_s3_credentials = false
+}
+
+# rotate the key by changing the keepers, the new key is created
+# before the old one is deleted
+resource "rgw_user_key" "example" {
+  user = rgw_user.example.id
+
+  keepers = {
+    rotation = "2023-01"
+  }
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+
+# only save the secret key encrypted in the state
+resource "rgw_user_key" "encrypted" {
+  user    = rgw_user.example.id
+  pgp_key = filebase64("public-key.gpg")
+}
+
+output "encrypted_secret_key" {
+  value = rgw_user_key.encrypted.encrypted_secret_key
+}
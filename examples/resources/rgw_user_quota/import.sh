@@ -0,0 +1,5 @@
+# import the quota of a user without tenant
+terraform import rgw_user_quota.example example
+
+# import the quota of a user of a tenant
+terraform import 'rgw_user_quota.example' 'tenant$example'
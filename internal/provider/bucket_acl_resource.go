@@ -0,0 +1,409 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithConfigure = &BucketAclResource{}
+var _ resource.ResourceWithImportState = &BucketAclResource{}
+var _ resource.ResourceWithValidateConfig = &BucketAclResource{}
+
+// group uris used by canned acls
+const (
+	aclAllUsersUri           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	aclAuthenticatedUsersUri = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+)
+
+// grants of the canned acls in addition to the FULL_CONTROL grant of the owner
+var cannedAclGrants = map[s3types.BucketCannedACL][]BucketAclGrantModel{
+	s3types.BucketCannedACLPrivate: {},
+	s3types.BucketCannedACLPublicRead: {
+		{Uri: types.StringValue(aclAllUsersUri), Permission: types.StringValue(string(s3types.PermissionRead))},
+	},
+	s3types.BucketCannedACLPublicReadWrite: {
+		{Uri: types.StringValue(aclAllUsersUri), Permission: types.StringValue(string(s3types.PermissionRead))},
+		{Uri: types.StringValue(aclAllUsersUri), Permission: types.StringValue(string(s3types.PermissionWrite))},
+	},
+	s3types.BucketCannedACLAuthenticatedRead: {
+		{Uri: types.StringValue(aclAuthenticatedUsersUri), Permission: types.StringValue(string(s3types.PermissionRead))},
+	},
+}
+
+func NewBucketAclResource() resource.Resource {
+	return &BucketAclResource{}
+}
+
+type BucketAclResource struct {
+	client *RgwClient
+}
+
+type BucketAclResourceModel struct {
+	Id     types.String          `tfsdk:"id"`
+	Bucket types.String          `tfsdk:"bucket"`
+	Acl    types.String          `tfsdk:"acl"`
+	Grants []BucketAclGrantModel `tfsdk:"grant"`
+	Owner  types.String          `tfsdk:"owner"`
+}
+
+type BucketAclGrantModel struct {
+	Id         types.String `tfsdk:"id"`
+	Uri        types.String `tfsdk:"uri"`
+	Permission types.String `tfsdk:"permission"`
+}
+
+func (r *BucketAclResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_acl"
+}
+
+func (r *BucketAclResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Access Control List of a bucket in Ceph RGW. Either a canned `acl` or `grant` blocks must be set. The owner of the bucket always keeps `FULL_CONTROL`, this grant is implicit and does not need to be configured. On destroy the acl is reset to `private`.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bucket": schema.StringAttribute{
+				MarkdownDescription: "Bucket Name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"acl": schema.StringAttribute{
+				MarkdownDescription: "Canned ACL. Valid values are `private`, `public-read`, `public-read-write` and `authenticated-read`. Conflicts with `grant`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(s3types.BucketCannedACLPrivate),
+						string(s3types.BucketCannedACLPublicRead),
+						string(s3types.BucketCannedACLPublicReadWrite),
+						string(s3types.BucketCannedACLAuthenticatedRead),
+					),
+				},
+			},
+			"owner": schema.StringAttribute{
+				MarkdownDescription: "Owner of the bucket, who implicitly has `FULL_CONTROL`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"grant": schema.SetNestedBlock{
+				MarkdownDescription: "Permission granted to a user or group. Conflicts with `acl`.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Canonical user id of the grantee, i.e. the RGW user id (`tenant$user` for users of a tenant). Conflicts with `uri`.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+								stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("uri")),
+							},
+						},
+						"uri": schema.StringAttribute{
+							MarkdownDescription: "URI of the grantee group, e.g. `" + aclAllUsersUri + "`. Conflicts with `id`.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(aclAllUsersUri, aclAuthenticatedUsersUri),
+							},
+						},
+						"permission": schema.StringAttribute{
+							MarkdownDescription: "Permission of the grantee. Valid values are `FULL_CONTROL`, `READ`, `WRITE`, `READ_ACP` and `WRITE_ACP`.",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(
+									string(s3types.PermissionFullControl),
+									string(s3types.PermissionRead),
+									string(s3types.PermissionWrite),
+									string(s3types.PermissionReadAcp),
+									string(s3types.PermissionWriteAcp),
+								),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *BucketAclResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *BucketAclResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *BucketAclResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// grant blocks are an empty set if not configured, so ConflictsWith can't be used
+	if !data.Acl.IsNull() && len(data.Grants) > 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("acl"), "conflicting acl", "acl can not be combined with grant blocks")
+	}
+	if data.Acl.IsNull() && len(data.Grants) == 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("acl"), "missing acl", "either acl or grant blocks must be set")
+	}
+}
+
+func (r *BucketAclResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketAclResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketAcl
+	if err := r.put(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not create bucket acl", err.Error())
+		return
+	}
+
+	// use bucket name as resource id
+	data.Id = types.StringValue(data.Bucket.ValueString())
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketAclResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketAclResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create GetBucketAcl Request
+	s3req := &s3.GetBucketAclInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	}
+
+	s3res, err := r.client.S3.GetBucketAcl(ctx, s3req)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == "NoSuchBucket" {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("could not get bucket acl", err.Error())
+		return
+	}
+
+	owner := ""
+	if s3res.Owner != nil {
+		owner = aws.StringValue(s3res.Owner.ID)
+	}
+	data.Owner = types.StringValue(owner)
+
+	// the FULL_CONTROL grant of the owner is implicit, unless it is
+	// configured explicitly
+	explicitOwnerGrant := false
+	for _, grant := range data.Grants {
+		if grant.Id.ValueString() == owner && grant.Permission.ValueString() == string(s3types.PermissionFullControl) {
+			explicitOwnerGrant = true
+		}
+	}
+
+	grants := make([]BucketAclGrantModel, 0, len(s3res.Grants))
+	for _, grant := range s3res.Grants {
+		if grant.Grantee == nil {
+			continue
+		}
+		id := aws.StringValue(grant.Grantee.ID)
+		if id == owner && grant.Permission == s3types.PermissionFullControl && !explicitOwnerGrant {
+			continue
+		}
+		grants = append(grants, BucketAclGrantModel{
+			Id:         stringOrNull(grant.Grantee.ID),
+			Uri:        stringOrNull(grant.Grantee.URI),
+			Permission: types.StringValue(string(grant.Permission)),
+		})
+	}
+
+	// canned acls are kept if the grants still match, imported acls prefer
+	// canned acls
+	imported := data.Acl.IsNull() && len(data.Grants) == 0
+	if !data.Acl.IsNull() || imported {
+		data.Acl = types.StringNull()
+		if canned := cannedAcl(grants); canned != "" {
+			data.Acl = types.StringValue(string(canned))
+			grants = []BucketAclGrantModel{}
+		}
+	}
+	data.Grants = grants
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketAclResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketAclResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketAcl
+	if err := r.put(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not modify bucket acl", err.Error())
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketAclResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketAclResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// an acl can't be deleted, reset it to the default
+	s3req := &s3.PutBucketAclInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+		ACL:    s3types.BucketCannedACLPrivate,
+	}
+
+	_, err := r.client.S3.PutBucketAcl(ctx, s3req)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == "NoSuchBucket" {
+			return
+		}
+		resp.Diagnostics.AddError("could not delete bucket acl", err.Error())
+		return
+	}
+}
+
+func (r *BucketAclResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	bucket, err := parseBucketImportId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("invalid import id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), bucket)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), bucket)...)
+}
+
+// put sets the acl of the bucket and the owner in the model. Explicit grants
+// need the owner of the bucket, so the current acl is read first.
+func (r *BucketAclResource) put(ctx context.Context, data *BucketAclResourceModel) error {
+	current, err := r.client.S3.GetBucketAcl(ctx, &s3.GetBucketAclInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	})
+	if err != nil {
+		return err
+	}
+	if current.Owner == nil || aws.StringValue(current.Owner.ID) == "" {
+		return fmt.Errorf("could not determine the owner of bucket '%s'", data.Bucket.ValueString())
+	}
+	owner := aws.StringValue(current.Owner.ID)
+	data.Owner = types.StringValue(owner)
+
+	s3req := &s3.PutBucketAclInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	}
+
+	if !data.Acl.IsNull() {
+		s3req.ACL = s3types.BucketCannedACL(data.Acl.ValueString())
+		_, err = r.client.S3.PutBucketAcl(ctx, s3req)
+		return err
+	}
+
+	ownerGrant := s3types.Grant{
+		Grantee:    &s3types.Grantee{Type: s3types.TypeCanonicalUser, ID: aws.String(owner)},
+		Permission: s3types.PermissionFullControl,
+	}
+	grants := []s3types.Grant{ownerGrant}
+	for _, grant := range data.Grants {
+		grantee := &s3types.Grantee{Type: s3types.TypeCanonicalUser, ID: stringPointer(grant.Id)}
+		if !grant.Uri.IsNull() {
+			grantee = &s3types.Grantee{Type: s3types.TypeGroup, URI: stringPointer(grant.Uri)}
+		}
+		// the implicit grant of the owner may be configured explicitly
+		if grant.Id.ValueString() == owner && grant.Permission.ValueString() == string(s3types.PermissionFullControl) {
+			continue
+		}
+		grants = append(grants, s3types.Grant{
+			Grantee:    grantee,
+			Permission: s3types.Permission(grant.Permission.ValueString()),
+		})
+	}
+
+	s3req.AccessControlPolicy = &s3types.AccessControlPolicy{
+		Owner:  &s3types.Owner{ID: aws.String(owner)},
+		Grants: grants,
+	}
+	_, err = r.client.S3.PutBucketAcl(ctx, s3req)
+	return err
+}
+
+// cannedAcl returns the canned acl matching the grants without the grant of the
+// owner, or an empty string if no canned acl matches.
+func cannedAcl(grants []BucketAclGrantModel) s3types.BucketCannedACL {
+	for canned, cannedGrants := range cannedAclGrants {
+		if len(grants) != len(cannedGrants) {
+			continue
+		}
+		matches := true
+		for _, grant := range grants {
+			found := false
+			for _, cannedGrant := range cannedGrants {
+				if grant.Id.IsNull() && grant.Uri.Equal(cannedGrant.Uri) && grant.Permission.Equal(cannedGrant.Permission) {
+					found = true
+				}
+			}
+			matches = matches && found
+		}
+		if matches {
+			return canned
+		}
+	}
+	return ""
+}
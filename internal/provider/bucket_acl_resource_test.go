@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBucketAclResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketAclResourceConfig(`acl = "public-read"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_acl.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket_acl.test", "acl", "public-read"),
+					resource.TestCheckResourceAttr("rgw_bucket_acl.test", "owner", fakeRgwS3User),
+					resource.TestCheckResourceAttr("rgw_bucket_acl.test", "grant.#", "0"),
+					testAccCheckBucketSubresource(fake, "test", "acl", aclAllUsersUri),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_bucket_acl.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing, the grant of the owner is implicit
+			{
+				Config: fake.providerConfig() + testAccBucketAclResourceConfig(`
+  grant {
+    id         = rgw_user.reader.id
+    permission = "READ"
+  }
+
+  grant {
+    uri        = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+    permission = "READ"
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("rgw_bucket_acl.test", "acl"),
+					resource.TestCheckResourceAttr("rgw_bucket_acl.test", "grant.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("rgw_bucket_acl.test", "grant.*", map[string]string{
+						"id":         "tenant$reader",
+						"permission": "READ",
+					}),
+					testAccCheckBucketSubresource(fake, "test", "acl", "tenant$reader"),
+					testAccCheckBucketSubresource(fake, "test", "acl", "FULL_CONTROL"),
+				),
+			},
+			// Drift testing, reset acls are restored
+			{
+				PreConfig: func() {
+					fake.modifyFakeBucket("test", func(bucket *fakeBucket) {
+						delete(bucket.subresources, "acl")
+					})
+				},
+				Config: fake.providerConfig() + testAccBucketAclResourceConfig(`
+  grant {
+    id         = rgw_user.reader.id
+    permission = "READ"
+  }
+
+  grant {
+    uri        = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+    permission = "READ"
+  }
+`),
+				Check: testAccCheckBucketSubresource(fake, "test", "acl", "tenant$reader"),
+			},
+			// Update testing, an explicit grant of the owner does not cause a diff
+			{
+				Config: fake.providerConfig() + testAccBucketAclResourceConfig(fmt.Sprintf(`
+  grant {
+    id         = %q
+    permission = "FULL_CONTROL"
+  }
+`, fakeRgwS3User)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_acl.test", "grant.#", "1"),
+					testAccCheckBucketSubresource(fake, "test", "acl", "FULL_CONTROL"),
+				),
+			},
+			// Delete testing, the bucket is kept with a private acl
+			{
+				Config: fake.providerConfig() + testAccBucketConfig,
+				Check:  testAccCheckBucketSubresourceDeleted(fake, "test", "acl"),
+			},
+		},
+	})
+}
+
+func TestAccBucketAclResource_invalid(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fake.providerConfig() + testAccBucketAclResourceConfig(``),
+				ExpectError: regexp.MustCompile(`missing acl`),
+			},
+			{
+				Config: fake.providerConfig() + testAccBucketAclResourceConfig(`
+  acl = "private"
+
+  grant {
+    id         = "reader"
+    permission = "READ"
+  }
+`),
+				ExpectError: regexp.MustCompile(`conflicting acl`),
+			},
+		},
+	})
+}
+
+func TestAccBucketAclResource_tenant(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "tenant:test"),
+		Steps: []resource.TestStep{
+			// grants to users which do not exist are rejected
+			{
+				Config: fake.providerConfig() + testAccBucketAclResourceTenantConfig(`
+  grant {
+    id         = "other$missing"
+    permission = "READ"
+  }
+`),
+				ExpectError: regexp.MustCompile(`InvalidArgument`),
+			},
+			// Create and Read testing, grants to users of the bucket tenant and of
+			// another tenant
+			{
+				Config: fake.providerConfig() + testAccBucketAclResourceTenantConfig(`
+  grant {
+    id         = rgw_user.reader.id
+    permission = "READ"
+  }
+
+  grant {
+    id         = rgw_user.writer.id
+    permission = "WRITE"
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_acl.test", "id", "tenant:test"),
+					resource.TestCheckResourceAttr("rgw_bucket_acl.test", "owner", fakeRgwS3User),
+					resource.TestCheckResourceAttr("rgw_bucket_acl.test", "grant.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("rgw_bucket_acl.test", "grant.*", map[string]string{
+						"id":         "other$reader",
+						"permission": "READ",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("rgw_bucket_acl.test", "grant.*", map[string]string{
+						"id":         "tenant$writer",
+						"permission": "WRITE",
+					}),
+					testAccCheckBucketSubresource(fake, "tenant:test", "acl", "other$reader"),
+					testAccCheckBucketSubresource(fake, "tenant:test", "acl", "tenant$writer"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_bucket_acl.test",
+				ImportState:       true,
+				ImportStateId:     "tenant/test",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccBucketAclResourceTenantConfig(acl string) string {
+	return testAccNamedUserConfig("reader", "other", "Reader") + testAccNamedUserConfig("writer", "tenant", "Writer") + fmt.Sprintf(`
+resource "rgw_bucket" "test" {
+  name   = "test"
+  tenant = "tenant"
+}
+
+resource "rgw_bucket_acl" "test" {
+  bucket = rgw_bucket.test.id
+  %s
+}
+`, acl)
+}
+
+func testAccBucketAclResourceConfig(acl string) string {
+	return testAccBucketConfig + testAccNamedUserConfig("reader", "tenant", "Reader") + fmt.Sprintf(`
+resource "rgw_bucket_acl" "test" {
+  bucket = rgw_bucket.test.name
+  %s
+}
+`, acl)
+}
@@ -0,0 +1,293 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithConfigure = &BucketCorsConfigurationResource{}
+var _ resource.ResourceWithImportState = &BucketCorsConfigurationResource{}
+
+func NewBucketCorsConfigurationResource() resource.Resource {
+	return &BucketCorsConfigurationResource{}
+}
+
+type BucketCorsConfigurationResource struct {
+	client *RgwClient
+}
+
+type BucketCorsConfigurationResourceModel struct {
+	Id     types.String          `tfsdk:"id"`
+	Bucket types.String          `tfsdk:"bucket"`
+	Rules  []BucketCorsRuleModel `tfsdk:"cors_rule"`
+}
+
+type BucketCorsRuleModel struct {
+	Id             types.String `tfsdk:"id"`
+	AllowedOrigins []string     `tfsdk:"allowed_origins"`
+	AllowedMethods []string     `tfsdk:"allowed_methods"`
+	AllowedHeaders []string     `tfsdk:"allowed_headers"`
+	ExposeHeaders  []string     `tfsdk:"expose_headers"`
+	MaxAgeSeconds  types.Int64  `tfsdk:"max_age_seconds"`
+}
+
+func (r *BucketCorsConfigurationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_cors_configuration"
+}
+
+func (r *BucketCorsConfigurationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Bucket CORS Configuration in Ceph RGW",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bucket": schema.StringAttribute{
+				MarkdownDescription: "Bucket Name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"cors_rule": schema.SetNestedBlock{
+				MarkdownDescription: "CORS rule. The order of rules is not significant.",
+				Validators: []validator.Set{
+					setvalidator.SizeBetween(1, 100),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Unique identifier of the rule",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.LengthBetween(1, 255),
+							},
+						},
+						"allowed_origins": schema.SetAttribute{
+							MarkdownDescription: "Origins allowed to access the bucket, `*` may be used as wildcard",
+							ElementType:         types.StringType,
+							Required:            true,
+						},
+						"allowed_methods": schema.SetAttribute{
+							MarkdownDescription: "HTTP methods allowed for the origins. Valid values are `GET`, `PUT`, `POST`, `DELETE` and `HEAD`.",
+							ElementType:         types.StringType,
+							Required:            true,
+							Validators: []validator.Set{
+								setvalidator.ValueStringsAre(stringvalidator.OneOf("GET", "PUT", "POST", "DELETE", "HEAD")),
+							},
+						},
+						"allowed_headers": schema.SetAttribute{
+							MarkdownDescription: "Headers allowed in preflight requests",
+							ElementType:         types.StringType,
+							Optional:            true,
+						},
+						"expose_headers": schema.SetAttribute{
+							MarkdownDescription: "Response headers accessible to the client",
+							ElementType:         types.StringType,
+							Optional:            true,
+						},
+						"max_age_seconds": schema.Int64Attribute{
+							MarkdownDescription: "Time in seconds the browser caches the preflight response",
+							Optional:            true,
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *BucketCorsConfigurationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *BucketCorsConfigurationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketCorsConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketCors
+	if err := r.put(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not create bucket cors configuration", err.Error())
+		return
+	}
+
+	// use bucket name as resource id
+	data.Id = types.StringValue(data.Bucket.ValueString())
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketCorsConfigurationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketCorsConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create GetBucketCors Request
+	s3req := &s3.GetBucketCorsInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	}
+
+	s3res, err := r.client.S3.GetBucketCors(ctx, s3req)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) {
+			switch ae.ErrorCode() {
+			case "NoSuchBucket", "NoSuchCORSConfiguration":
+				resp.State.RemoveResource(ctx)
+				return
+			}
+		}
+		resp.Diagnostics.AddError("could not get bucket cors configuration", err.Error())
+		return
+	}
+
+	data.Rules = make([]BucketCorsRuleModel, len(s3res.CORSRules))
+	for i, rule := range s3res.CORSRules {
+		data.Rules[i] = BucketCorsRuleModel{
+			Id:             types.StringNull(),
+			AllowedOrigins: rule.AllowedOrigins,
+			AllowedMethods: rule.AllowedMethods,
+			AllowedHeaders: nilIfEmpty(rule.AllowedHeaders),
+			ExposeHeaders:  nilIfEmpty(rule.ExposeHeaders),
+			MaxAgeSeconds:  types.Int64Null(),
+		}
+		if aws.StringValue(rule.ID) != "" {
+			data.Rules[i].Id = types.StringValue(*rule.ID)
+		}
+		if rule.MaxAgeSeconds > 0 {
+			data.Rules[i].MaxAgeSeconds = types.Int64Value(int64(rule.MaxAgeSeconds))
+		}
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketCorsConfigurationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketCorsConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketCors
+	if err := r.put(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not modify bucket cors configuration", err.Error())
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketCorsConfigurationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketCorsConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	s3req := &s3.DeleteBucketCorsInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	}
+
+	_, err := r.client.S3.DeleteBucketCors(ctx, s3req)
+	if err != nil {
+		resp.Diagnostics.AddError("could not delete bucket cors configuration", err.Error())
+		return
+	}
+}
+
+func (r *BucketCorsConfigurationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	bucket, err := parseBucketImportId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("invalid import id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), bucket)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), bucket)...)
+}
+
+func (r *BucketCorsConfigurationResource) put(ctx context.Context, data *BucketCorsConfigurationResourceModel) error {
+	rules := make([]s3types.CORSRule, len(data.Rules))
+	for i, rule := range data.Rules {
+		rules[i] = s3types.CORSRule{
+			AllowedOrigins: rule.AllowedOrigins,
+			AllowedMethods: rule.AllowedMethods,
+			AllowedHeaders: rule.AllowedHeaders,
+			ExposeHeaders:  rule.ExposeHeaders,
+			MaxAgeSeconds:  int32(rule.MaxAgeSeconds.ValueInt64()),
+		}
+		if !rule.Id.IsNull() {
+			rules[i].ID = aws.String(rule.Id.ValueString())
+		}
+	}
+
+	_, err := r.client.S3.PutBucketCors(ctx, &s3.PutBucketCorsInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+		CORSConfiguration: &s3types.CORSConfiguration{
+			CORSRules: rules,
+		},
+	})
+	return err
+}
+
+func nilIfEmpty(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
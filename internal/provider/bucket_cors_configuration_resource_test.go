@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBucketCorsConfigurationResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketCorsConfigurationResourceConfig("https://example.com"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_cors_configuration.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket_cors_configuration.test", "cors_rule.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("rgw_bucket_cors_configuration.test", "cors_rule.*", map[string]string{
+						"allowed_origins.#": "1",
+						"allowed_methods.#": "2",
+						"max_age_seconds":   "3600",
+					}),
+					testAccCheckBucketSubresource(fake, "test", "cors", "https://example.com"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_bucket_cors_configuration.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketCorsConfigurationResourceConfig("https://example.org"),
+				Check:  testAccCheckBucketSubresource(fake, "test", "cors", "https://example.org"),
+			},
+			// Drift testing, deleted configurations are restored
+			{
+				PreConfig: func() {
+					fake.modifyFakeBucket("test", func(bucket *fakeBucket) {
+						delete(bucket.subresources, "cors")
+					})
+				},
+				Config: fake.providerConfig() + testAccBucketCorsConfigurationResourceConfig("https://example.org"),
+				Check:  testAccCheckBucketSubresource(fake, "test", "cors", "https://example.org"),
+			},
+			// Delete testing, the bucket is kept
+			{
+				Config: fake.providerConfig() + testAccBucketConfig,
+				Check:  testAccCheckBucketSubresourceDeleted(fake, "test", "cors"),
+			},
+		},
+	})
+}
+
+func testAccBucketCorsConfigurationResourceConfig(origin string) string {
+	return testAccBucketConfig + fmt.Sprintf(`
+resource "rgw_bucket_cors_configuration" "test" {
+  bucket = rgw_bucket.test.name
+
+  cors_rule {
+    allowed_origins = [%[1]q]
+    allowed_methods = ["GET", "HEAD"]
+    max_age_seconds = 3600
+  }
+}
+`, origin)
+}
@@ -0,0 +1,187 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSourceWithConfigure = &BucketDataSource{}
+
+func NewBucketDataSource() datasource.DataSource {
+	return &BucketDataSource{}
+}
+
+type BucketDataSource struct {
+	client *RgwClient
+}
+
+type BucketDataSourceModel struct {
+	Id            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Tenant        types.String `tfsdk:"tenant"`
+	Owner         types.String `tfsdk:"owner"`
+	Zonegroup     types.String `tfsdk:"zonegroup"`
+	PlacementRule types.String `tfsdk:"placement_rule"`
+	Versioning    types.String `tfsdk:"versioning"`
+	Size          types.Int64  `tfsdk:"size"`
+	NumObjects    types.Int64  `tfsdk:"num_objects"`
+	CreationTime  types.String `tfsdk:"creation_time"`
+}
+
+func (d *BucketDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket"
+}
+
+func (d *BucketDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Bucket in Ceph RGW",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Bucket name as used in S3 requests (`tenant:bucket` for buckets of a tenant)",
+				Computed:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Bucket Name",
+				Required:            true,
+			},
+			"tenant": schema.StringAttribute{
+				MarkdownDescription: "The tenant of the bucket",
+				Optional:            true,
+			},
+			"owner": schema.StringAttribute{
+				MarkdownDescription: "The user ID of the bucket owner",
+				Computed:            true,
+			},
+			"zonegroup": schema.StringAttribute{
+				MarkdownDescription: "The zonegroup of the bucket",
+				Computed:            true,
+			},
+			"placement_rule": schema.StringAttribute{
+				MarkdownDescription: "The placement rule of the bucket (`<placement-target>[/<storage-class>]`)",
+				Computed:            true,
+			},
+			"versioning": schema.StringAttribute{
+				MarkdownDescription: "Versioning state of the bucket (`Enabled` or `Suspended`), null if versioning was never enabled or the provider identity may not read it",
+				Computed:            true,
+			},
+			"size": schema.Int64Attribute{
+				MarkdownDescription: "Size of all objects in the bucket in bytes",
+				Computed:            true,
+			},
+			"num_objects": schema.Int64Attribute{
+				MarkdownDescription: "Number of objects in the bucket",
+				Computed:            true,
+			},
+			"creation_time": schema.StringAttribute{
+				MarkdownDescription: "Creation time of the bucket (RFC3339)",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *BucketDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *BucketDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Read Terraform configuration data into the model
+	var data *BucketDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	bucket := data.Name.ValueString()
+	if !data.Tenant.IsNull() {
+		bucket = fmt.Sprintf("%s:%s", data.Tenant.ValueString(), data.Name.ValueString())
+	}
+
+	// get bucket info
+	info, err := d.client.Admin.GetBucketInfo(ctx, admin.Bucket{
+		Bucket: adminBucketName(bucket),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("could not get bucket info", err.Error())
+		return
+	}
+
+	data.Id = types.StringValue(bucket)
+	data.Owner = types.StringValue(info.Owner)
+	data.Zonegroup = types.StringValue(info.Zonegroup)
+	data.PlacementRule = types.StringValue(info.PlacementRule)
+
+	creationTime, err := parseRgwTime(info.Mtime)
+	if err != nil {
+		resp.Diagnostics.AddError("could not parse bucket creation time", err.Error())
+		return
+	}
+	data.CreationTime = types.StringValue(creationTime.Format(time.RFC3339))
+
+	data.Size = types.Int64Value(0)
+	if info.Usage.RgwMain.Size != nil {
+		data.Size = types.Int64Value(int64(*info.Usage.RgwMain.Size))
+	}
+	data.NumObjects = types.Int64Value(0)
+	if info.Usage.RgwMain.NumObjects != nil {
+		data.NumObjects = types.Int64Value(int64(*info.Usage.RgwMain.NumObjects))
+	}
+
+	// get versioning, which is not part of the bucket info
+	data.Versioning = types.StringNull()
+	versioning, err := d.client.S3.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		var ae smithy.APIError
+		if !errors.As(err, &ae) || ae.ErrorCode() != "AccessDenied" {
+			resp.Diagnostics.AddError("could not get bucket versioning", err.Error())
+			return
+		}
+		resp.Diagnostics.AddAttributeWarning(path.Root("versioning"), "no permission to get bucket versioning", err.Error())
+	} else if versioning.Status != "" {
+		data.Versioning = types.StringValue(string(versioning.Status))
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// parseRgwTime parses a time of the RGW admin API, which older releases format
+// with a space instead of "T".
+func parseRgwTime(value string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02T15:04:05.999999999Z", value)
+	if err != nil {
+		t, err = time.Parse("2006-01-02 15:04:05.999999999Z", value)
+	}
+	return t, err
+}
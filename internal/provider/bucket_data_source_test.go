@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBucketDataSource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.rgw_bucket.test", "id", "test"),
+					resource.TestCheckResourceAttr("data.rgw_bucket.test", "owner", fakeRgwS3User),
+					resource.TestCheckResourceAttr("data.rgw_bucket.test", "placement_rule", "default-placement"),
+					resource.TestCheckResourceAttr("data.rgw_bucket.test", "versioning", "Enabled"),
+					resource.TestMatchResourceAttr("data.rgw_bucket.test", "creation_time", regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestParseRgwTime(t *testing.T) {
+	for _, value := range []string{"2023-01-10T10:12:53.456789Z", "2023-01-10 10:12:53.456789Z"} {
+		got, err := parseRgwTime(value)
+		if err != nil {
+			t.Fatalf("could not parse '%s': %s", value, err)
+		}
+		if want := time.Date(2023, 1, 10, 10, 12, 53, 456789000, time.UTC); !got.Equal(want) {
+			t.Errorf("expected %s for '%s', got %s", want, value, got)
+		}
+	}
+}
+
+const testAccBucketDataSourceConfig = `
+resource "rgw_bucket" "test" {
+  name       = "test"
+  versioning = "Enabled"
+}
+
+data "rgw_bucket" "test" {
+  name = rgw_bucket.test.name
+}
+`
@@ -0,0 +1,558 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithConfigure = &BucketLifecycleConfigurationResource{}
+var _ resource.ResourceWithImportState = &BucketLifecycleConfigurationResource{}
+
+func NewBucketLifecycleConfigurationResource() resource.Resource {
+	return &BucketLifecycleConfigurationResource{}
+}
+
+type BucketLifecycleConfigurationResource struct {
+	client *RgwClient
+}
+
+type BucketLifecycleConfigurationResourceModel struct {
+	Id     types.String               `tfsdk:"id"`
+	Bucket types.String               `tfsdk:"bucket"`
+	Rules  []BucketLifecycleRuleModel `tfsdk:"rule"`
+}
+
+type BucketLifecycleRuleModel struct {
+	Id                                 types.String                           `tfsdk:"id"`
+	Status                             types.String                           `tfsdk:"status"`
+	Prefix                             types.String                           `tfsdk:"prefix"`
+	Tags                               map[string]string                      `tfsdk:"tags"`
+	Expiration                         *BucketLifecycleExpirationModel        `tfsdk:"expiration"`
+	NoncurrentVersionExpiration        *BucketLifecycleNoncurrentVersionModel `tfsdk:"noncurrent_version_expiration"`
+	AbortIncompleteMultipartUploadDays types.Int64                            `tfsdk:"abort_incomplete_multipart_upload_days"`
+	Transitions                        []BucketLifecycleTransitionModel       `tfsdk:"transitions"`
+}
+
+type BucketLifecycleExpirationModel struct {
+	Days                      types.Int64  `tfsdk:"days"`
+	Date                      types.String `tfsdk:"date"`
+	ExpiredObjectDeleteMarker types.Bool   `tfsdk:"expired_object_delete_marker"`
+}
+
+type BucketLifecycleNoncurrentVersionModel struct {
+	NoncurrentDays          types.Int64 `tfsdk:"noncurrent_days"`
+	NewerNoncurrentVersions types.Int64 `tfsdk:"newer_noncurrent_versions"`
+}
+
+type BucketLifecycleTransitionModel struct {
+	Days         types.Int64  `tfsdk:"days"`
+	Date         types.String `tfsdk:"date"`
+	StorageClass types.String `tfsdk:"storage_class"`
+}
+
+func (r *BucketLifecycleConfigurationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_lifecycle_configuration"
+}
+
+func (r *BucketLifecycleConfigurationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Bucket Lifecycle Configuration in Ceph RGW",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bucket": schema.StringAttribute{
+				MarkdownDescription: "Bucket Name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"rule": schema.SetNestedBlock{
+				MarkdownDescription: "Lifecycle rule. The order of rules is not significant.",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Unique identifier of the rule",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.LengthBetween(1, 255),
+							},
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "Whether the rule is applied. One of `Enabled` or `Disabled`.",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(string(s3types.ExpirationStatusEnabled), string(s3types.ExpirationStatusDisabled)),
+							},
+						},
+						"prefix": schema.StringAttribute{
+							MarkdownDescription: "Only apply the rule to objects with this key prefix",
+							Optional:            true,
+						},
+						"tags": schema.MapAttribute{
+							MarkdownDescription: "Only apply the rule to objects having all of these tags",
+							ElementType:         types.StringType,
+							Optional:            true,
+						},
+						"expiration": schema.SingleNestedAttribute{
+							MarkdownDescription: "Expire current object versions",
+							Optional:            true,
+							Attributes: map[string]schema.Attribute{
+								"days": schema.Int64Attribute{
+									MarkdownDescription: "Number of days after object creation",
+									Optional:            true,
+									Validators: []validator.Int64{
+										int64validator.AtLeast(1),
+										int64validator.ConflictsWith(path.MatchRelative().AtParent().AtName("date")),
+									},
+								},
+								"date": schema.StringAttribute{
+									MarkdownDescription: "Date in RFC3339 format, e.g. `2023-01-01T00:00:00Z`",
+									Optional:            true,
+									Validators: []validator.String{
+										rfc3339Validator{},
+									},
+								},
+								"expired_object_delete_marker": schema.BoolAttribute{
+									MarkdownDescription: "Remove delete markers without noncurrent versions. `false` is treated like not set.",
+									Optional:            true,
+								},
+							},
+						},
+						"noncurrent_version_expiration": schema.SingleNestedAttribute{
+							MarkdownDescription: "Expire noncurrent object versions",
+							Optional:            true,
+							Attributes: map[string]schema.Attribute{
+								"noncurrent_days": schema.Int64Attribute{
+									MarkdownDescription: "Number of days after an object version became noncurrent",
+									Required:            true,
+									Validators: []validator.Int64{
+										int64validator.AtLeast(1),
+									},
+								},
+								"newer_noncurrent_versions": schema.Int64Attribute{
+									MarkdownDescription: "Number of noncurrent versions to retain",
+									Optional:            true,
+									Validators: []validator.Int64{
+										int64validator.AtLeast(1),
+									},
+								},
+							},
+						},
+						"abort_incomplete_multipart_upload_days": schema.Int64Attribute{
+							MarkdownDescription: "Number of days after which incomplete multipart uploads are aborted",
+							Optional:            true,
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+							},
+						},
+						"transitions": schema.SetNestedAttribute{
+							MarkdownDescription: "Transition current object versions to another storage class",
+							Optional:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"days": schema.Int64Attribute{
+										MarkdownDescription: "Number of days after object creation",
+										Optional:            true,
+										Validators: []validator.Int64{
+											int64validator.AtLeast(1),
+											int64validator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("date")),
+										},
+									},
+									"date": schema.StringAttribute{
+										MarkdownDescription: "Date in RFC3339 format, e.g. `2023-01-01T00:00:00Z`",
+										Optional:            true,
+										Validators: []validator.String{
+											rfc3339Validator{},
+										},
+									},
+									"storage_class": schema.StringAttribute{
+										MarkdownDescription: "RGW storage class of the placement target",
+										Required:            true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *BucketLifecycleConfigurationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *BucketLifecycleConfigurationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketLifecycleConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketLifecycleConfiguration
+	if err := r.put(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not create bucket lifecycle configuration", err.Error())
+		return
+	}
+
+	// use bucket name as resource id
+	data.Id = types.StringValue(data.Bucket.ValueString())
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketLifecycleConfigurationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketLifecycleConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create GetBucketLifecycleConfiguration Request
+	s3req := &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	}
+
+	s3res, err := r.client.S3.GetBucketLifecycleConfiguration(ctx, s3req)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) {
+			switch ae.ErrorCode() {
+			case "NoSuchBucket", "NoSuchLifecycleConfiguration":
+				resp.State.RemoveResource(ctx)
+				return
+			}
+		}
+		resp.Diagnostics.AddError("could not get bucket lifecycle configuration", err.Error())
+		return
+	}
+
+	// rules are matched by id to keep configured values RGW does not return
+	priorRules := make(map[string]BucketLifecycleRuleModel, len(data.Rules))
+	for _, rule := range data.Rules {
+		priorRules[rule.Id.ValueString()] = rule
+	}
+
+	data.Rules = make([]BucketLifecycleRuleModel, len(s3res.Rules))
+	for i, rule := range s3res.Rules {
+		data.Rules[i] = flattenLifecycleRule(rule)
+		if prior, ok := priorRules[data.Rules[i].Id.ValueString()]; ok {
+			keepConfiguredLifecycleValues(&data.Rules[i], prior)
+		}
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketLifecycleConfigurationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketLifecycleConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketLifecycleConfiguration
+	if err := r.put(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not modify bucket lifecycle configuration", err.Error())
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketLifecycleConfigurationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketLifecycleConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	s3req := &s3.DeleteBucketLifecycleInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	}
+
+	_, err := r.client.S3.DeleteBucketLifecycle(ctx, s3req)
+	if err != nil {
+		resp.Diagnostics.AddError("could not delete bucket lifecycle configuration", err.Error())
+		return
+	}
+}
+
+func (r *BucketLifecycleConfigurationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	bucket, err := parseBucketImportId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("invalid import id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), bucket)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), bucket)...)
+}
+
+func (r *BucketLifecycleConfigurationResource) put(ctx context.Context, data *BucketLifecycleConfigurationResourceModel) error {
+	rules := make([]s3types.LifecycleRule, len(data.Rules))
+	for i, rule := range data.Rules {
+		var err error
+		rules[i], err = expandLifecycleRule(rule)
+		if err != nil {
+			return fmt.Errorf("invalid rule '%s': %w", rule.Id.ValueString(), err)
+		}
+	}
+
+	_, err := r.client.S3.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+		LifecycleConfiguration: &s3types.BucketLifecycleConfiguration{
+			Rules: rules,
+		},
+	})
+	return err
+}
+
+func expandLifecycleRule(rule BucketLifecycleRuleModel) (s3types.LifecycleRule, error) {
+	res := s3types.LifecycleRule{
+		ID:     aws.String(rule.Id.ValueString()),
+		Status: s3types.ExpirationStatus(rule.Status.ValueString()),
+	}
+
+	// filter
+	if len(rule.Tags) == 0 {
+		res.Filter = &s3types.LifecycleRuleFilterMemberPrefix{Value: rule.Prefix.ValueString()}
+	} else if len(rule.Tags) == 1 && rule.Prefix.IsNull() {
+		for k, v := range rule.Tags {
+			res.Filter = &s3types.LifecycleRuleFilterMemberTag{Value: s3types.Tag{Key: aws.String(k), Value: aws.String(v)}}
+		}
+	} else {
+		and := s3types.LifecycleRuleAndOperator{}
+		if !rule.Prefix.IsNull() {
+			and.Prefix = aws.String(rule.Prefix.ValueString())
+		}
+		for k, v := range rule.Tags {
+			and.Tags = append(and.Tags, s3types.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+		res.Filter = &s3types.LifecycleRuleFilterMemberAnd{Value: and}
+	}
+
+	// expiration
+	if rule.Expiration != nil {
+		res.Expiration = &s3types.LifecycleExpiration{
+			Days:                      int32(rule.Expiration.Days.ValueInt64()),
+			ExpiredObjectDeleteMarker: rule.Expiration.ExpiredObjectDeleteMarker.ValueBool(),
+		}
+		if !rule.Expiration.Date.IsNull() {
+			date, err := time.Parse(time.RFC3339, rule.Expiration.Date.ValueString())
+			if err != nil {
+				return res, err
+			}
+			res.Expiration.Date = &date
+		}
+	}
+
+	// noncurrent version expiration
+	if rule.NoncurrentVersionExpiration != nil {
+		res.NoncurrentVersionExpiration = &s3types.NoncurrentVersionExpiration{
+			NoncurrentDays:          int32(rule.NoncurrentVersionExpiration.NoncurrentDays.ValueInt64()),
+			NewerNoncurrentVersions: int32(rule.NoncurrentVersionExpiration.NewerNoncurrentVersions.ValueInt64()),
+		}
+	}
+
+	// abort incomplete multipart uploads
+	if !rule.AbortIncompleteMultipartUploadDays.IsNull() {
+		res.AbortIncompleteMultipartUpload = &s3types.AbortIncompleteMultipartUpload{
+			DaysAfterInitiation: int32(rule.AbortIncompleteMultipartUploadDays.ValueInt64()),
+		}
+	}
+
+	// transitions
+	for _, t := range rule.Transitions {
+		transition := s3types.Transition{
+			Days:         int32(t.Days.ValueInt64()),
+			StorageClass: s3types.TransitionStorageClass(t.StorageClass.ValueString()),
+		}
+		if !t.Date.IsNull() {
+			date, err := time.Parse(time.RFC3339, t.Date.ValueString())
+			if err != nil {
+				return res, err
+			}
+			transition.Date = &date
+		}
+		res.Transitions = append(res.Transitions, transition)
+	}
+
+	return res, nil
+}
+
+// flattenLifecycleRule converts a rule returned by the api into the model.
+// Zero values are mapped to null, so rules read back match the configuration.
+func flattenLifecycleRule(rule s3types.LifecycleRule) BucketLifecycleRuleModel {
+	res := BucketLifecycleRuleModel{
+		Id:                                 types.StringValue(aws.StringValue(rule.ID)),
+		Status:                             types.StringValue(string(rule.Status)),
+		Prefix:                             types.StringNull(),
+		AbortIncompleteMultipartUploadDays: types.Int64Null(),
+	}
+
+	// filter
+	prefix := aws.StringValue(rule.Prefix)
+	switch f := rule.Filter.(type) {
+	case *s3types.LifecycleRuleFilterMemberPrefix:
+		prefix = f.Value
+	case *s3types.LifecycleRuleFilterMemberTag:
+		res.Tags = map[string]string{aws.StringValue(f.Value.Key): aws.StringValue(f.Value.Value)}
+	case *s3types.LifecycleRuleFilterMemberAnd:
+		prefix = aws.StringValue(f.Value.Prefix)
+		if len(f.Value.Tags) > 0 {
+			res.Tags = make(map[string]string, len(f.Value.Tags))
+			for _, t := range f.Value.Tags {
+				res.Tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+			}
+		}
+	}
+	if prefix != "" {
+		res.Prefix = types.StringValue(prefix)
+	}
+
+	// expiration
+	if rule.Expiration != nil {
+		res.Expiration = &BucketLifecycleExpirationModel{
+			Days:                      int64OrNull(int64(rule.Expiration.Days)),
+			Date:                      timeOrNull(rule.Expiration.Date),
+			ExpiredObjectDeleteMarker: types.BoolNull(),
+		}
+		if rule.Expiration.ExpiredObjectDeleteMarker {
+			res.Expiration.ExpiredObjectDeleteMarker = types.BoolValue(true)
+		}
+	}
+
+	// noncurrent version expiration
+	if rule.NoncurrentVersionExpiration != nil {
+		res.NoncurrentVersionExpiration = &BucketLifecycleNoncurrentVersionModel{
+			NoncurrentDays:          types.Int64Value(int64(rule.NoncurrentVersionExpiration.NoncurrentDays)),
+			NewerNoncurrentVersions: int64OrNull(int64(rule.NoncurrentVersionExpiration.NewerNoncurrentVersions)),
+		}
+	}
+
+	// abort incomplete multipart uploads
+	if rule.AbortIncompleteMultipartUpload != nil {
+		res.AbortIncompleteMultipartUploadDays = types.Int64Value(int64(rule.AbortIncompleteMultipartUpload.DaysAfterInitiation))
+	}
+
+	// transitions
+	for _, t := range rule.Transitions {
+		transition := BucketLifecycleTransitionModel{
+			Days:         types.Int64Null(),
+			Date:         timeOrNull(t.Date),
+			StorageClass: types.StringValue(string(t.StorageClass)),
+		}
+		if t.Date == nil {
+			transition.Days = types.Int64Value(int64(t.Days))
+		}
+		res.Transitions = append(res.Transitions, transition)
+	}
+
+	return res
+}
+
+// keepConfiguredLifecycleValues restores configured values of a rule which RGW
+// returns as null or in another format: an empty prefix,
+// expired_object_delete_marker set to false and dates with a time zone offset.
+func keepConfiguredLifecycleValues(rule *BucketLifecycleRuleModel, prior BucketLifecycleRuleModel) {
+	if rule.Prefix.IsNull() && !prior.Prefix.IsNull() && prior.Prefix.ValueString() == "" {
+		rule.Prefix = prior.Prefix
+	}
+
+	if rule.Expiration != nil && prior.Expiration != nil {
+		eodm := prior.Expiration.ExpiredObjectDeleteMarker
+		if rule.Expiration.ExpiredObjectDeleteMarker.IsNull() && !eodm.IsNull() && !eodm.ValueBool() {
+			rule.Expiration.ExpiredObjectDeleteMarker = eodm
+		}
+		if sameTime(rule.Expiration.Date, prior.Expiration.Date) {
+			rule.Expiration.Date = prior.Expiration.Date
+		}
+	}
+
+	for i, t := range rule.Transitions {
+		for _, p := range prior.Transitions {
+			if t.StorageClass.Equal(p.StorageClass) && sameTime(t.Date, p.Date) {
+				rule.Transitions[i].Date = p.Date
+			}
+		}
+	}
+}
+
+// sameTime returns whether two dates in RFC3339 format are the same point in
+// time.
+func sameTime(a types.String, b types.String) bool {
+	if a.IsNull() || b.IsNull() {
+		return false
+	}
+	timeA, errA := time.Parse(time.RFC3339, a.ValueString())
+	timeB, errB := time.Parse(time.RFC3339, b.ValueString())
+	return errA == nil && errB == nil && timeA.Equal(timeB)
+}
+
+func int64OrNull(v int64) types.Int64 {
+	if v == 0 {
+		return types.Int64Null()
+	}
+	return types.Int64Value(v)
+}
+
+func timeOrNull(t *time.Time) types.String {
+	if t == nil {
+		return types.StringNull()
+	}
+	return types.StringValue(t.UTC().Format(time.RFC3339))
+}
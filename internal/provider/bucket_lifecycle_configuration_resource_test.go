@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBucketLifecycleConfigurationResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketLifecycleConfigurationResourceConfig(30),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_lifecycle_configuration.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket_lifecycle_configuration.test", "rule.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("rgw_bucket_lifecycle_configuration.test", "rule.*", map[string]string{
+						"id":              "expire-logs",
+						"status":          "Enabled",
+						"prefix":          "logs/",
+						"expiration.days": "30",
+					}),
+					resource.TestCheckTypeSetElemNestedAttrs("rgw_bucket_lifecycle_configuration.test", "rule.*", map[string]string{
+						"id":                                     "abort-uploads",
+						"status":                                 "Enabled",
+						"abort_incomplete_multipart_upload_days": "7",
+					}),
+					testAccCheckBucketSubresource(fake, "test", "lifecycle", "expire-logs"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_bucket_lifecycle_configuration.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketLifecycleConfigurationResourceConfig(90),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("rgw_bucket_lifecycle_configuration.test", "rule.*", map[string]string{
+						"id":              "expire-logs",
+						"expiration.days": "90",
+					}),
+					testAccCheckBucketSubresource(fake, "test", "lifecycle", "<Days>90</Days>"),
+				),
+			},
+			// Drift testing, deleted configurations are restored
+			{
+				PreConfig: func() {
+					fake.modifyFakeBucket("test", func(bucket *fakeBucket) {
+						delete(bucket.subresources, "lifecycle")
+					})
+				},
+				Config: fake.providerConfig() + testAccBucketLifecycleConfigurationResourceConfig(90),
+				Check:  testAccCheckBucketSubresource(fake, "test", "lifecycle", "<Days>90</Days>"),
+			},
+			// Delete testing, the bucket is kept
+			{
+				Config: fake.providerConfig() + testAccBucketConfig,
+				Check:  testAccCheckBucketSubresourceDeleted(fake, "test", "lifecycle"),
+			},
+		},
+	})
+}
+
+func TestAccBucketLifecycleConfigurationResource_configuredValues(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			// values RGW returns as null or in UTC are kept as configured, the
+			// plan after apply must be empty
+			{
+				Config: fake.providerConfig() + testAccBucketConfig + `
+resource "rgw_bucket_lifecycle_configuration" "test" {
+  bucket = rgw_bucket.test.name
+
+  rule {
+    id     = "expire"
+    status = "Enabled"
+    prefix = ""
+
+    expiration = {
+      date                         = "2030-01-01T01:00:00+01:00"
+      expired_object_delete_marker = false
+    }
+
+    transitions = [{
+      date          = "2029-01-01T01:00:00+01:00"
+      storage_class = "COLD"
+    }]
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckTypeSetElemNestedAttrs("rgw_bucket_lifecycle_configuration.test", "rule.*", map[string]string{
+						"prefix":          "",
+						"expiration.date": "2030-01-01T01:00:00+01:00",
+						"expiration.expired_object_delete_marker": "false",
+					}),
+					testAccCheckBucketSubresource(fake, "test", "lifecycle", "2030-01-01T00:00:00Z"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBucketLifecycleConfigurationResourceConfig(days int) string {
+	return testAccBucketConfig + fmt.Sprintf(`
+resource "rgw_bucket_lifecycle_configuration" "test" {
+  bucket = rgw_bucket.test.name
+
+  rule {
+    id     = "expire-logs"
+    status = "Enabled"
+    prefix = "logs/"
+
+    expiration = {
+      days = %[1]d
+    }
+  }
+
+  rule {
+    id     = "abort-uploads"
+    status = "Enabled"
+
+    abort_incomplete_multipart_upload_days = 7
+  }
+}
+`, days)
+}
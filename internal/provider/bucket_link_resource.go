@@ -0,0 +1,240 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithConfigure = &BucketLinkResource{}
+var _ resource.ResourceWithImportState = &BucketLinkResource{}
+var _ resource.ResourceWithModifyPlan = &BucketLinkResource{}
+
+func NewBucketLinkResource() resource.Resource {
+	return &BucketLinkResource{}
+}
+
+type BucketLinkResource struct {
+	client *RgwClient
+}
+
+type BucketLinkResourceModel struct {
+	Id                   types.String `tfsdk:"id"`
+	Bucket               types.String `tfsdk:"bucket"`
+	Uid                  types.String `tfsdk:"uid"`
+	PreviousOwner        types.String `tfsdk:"previous_owner"`
+	RestorePreviousOwner types.Bool   `tfsdk:"restore_previous_owner_on_destroy"`
+}
+
+func (r *BucketLinkResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_link"
+}
+
+func (r *BucketLinkResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Links a bucket to a Ceph RGW User, which transfers the ownership of the bucket. If the user belongs to another tenant, the bucket is moved into the tenant of the user. Do not use together with `owner` of `rgw_bucket`, and do not move buckets managed by `rgw_bucket` into another tenant.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Bucket name as used in S3 requests after linking, including the tenant of the user (`tenant:bucket`)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bucket": schema.StringAttribute{
+				MarkdownDescription: "Bucket name as used in S3 requests (`tenant:bucket`), e.g. the `id` of a `rgw_bucket`.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"uid": schema.StringAttribute{
+				MarkdownDescription: "The user ID including the tenant (`tenant$user`) to link the bucket to, e.g. the `id` of a `rgw_user`.",
+				Required:            true,
+			},
+			"previous_owner": schema.StringAttribute{
+				MarkdownDescription: "Owner of the bucket before it was linked to `uid`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"restore_previous_owner_on_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Link the bucket to `previous_owner` again on destroy. Otherwise the bucket stays linked to `uid`. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolDefaultModifier{false},
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *BucketLinkResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to do on create and destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state *BucketLinkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the id changes when the bucket is moved into the tenant of another user
+	if plan.Uid.IsUnknown() {
+		plan.Id = types.StringUnknown()
+	} else if !plan.Uid.Equal(state.Uid) {
+		plan.Id = types.StringValue(bucketId(userTenant(plan.Uid.ValueString()), bucketName(state.Id.ValueString())))
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *BucketLinkResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *BucketLinkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketLinkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// remember the previous owner
+	info, err := r.client.Admin.GetBucketInfo(ctx, admin.Bucket{
+		Bucket: adminBucketName(data.Bucket.ValueString()),
+	})
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("bucket"), "could not get bucket info", err.Error())
+		return
+	}
+	data.PreviousOwner = types.StringValue(info.Owner)
+
+	// link bucket
+	if err := linkBucket(ctx, r.client.Admin, data.Bucket.ValueString(), data.Uid.ValueString()); err != nil {
+		resp.Diagnostics.AddError("could not link bucket", err.Error())
+		return
+	}
+	data.Id = types.StringValue(bucketId(userTenant(data.Uid.ValueString()), bucketName(data.Bucket.ValueString())))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketLinkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketLinkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// get bucket
+	info, err := r.client.Admin.GetBucketInfo(ctx, admin.Bucket{
+		Bucket: adminBucketName(data.Id.ValueString()),
+	})
+	if err != nil {
+		if errors.Is(err, admin.ErrNoSuchBucket) {
+			// the bucket was deleted or moved into another tenant
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("could not get bucket info", err.Error())
+		return
+	}
+
+	// detect changes of the owner, which are reverted on the next apply
+	data.Uid = types.StringValue(info.Owner)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketLinkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read Terraform plan and prior state data into the models
+	var data, state *BucketLinkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// link bucket to the new user
+	data.Id = state.Id
+	if !data.Uid.Equal(state.Uid) {
+		if err := linkBucket(ctx, r.client.Admin, state.Id.ValueString(), data.Uid.ValueString()); err != nil {
+			resp.Diagnostics.AddError("could not link bucket", err.Error())
+			return
+		}
+		data.Id = types.StringValue(bucketId(userTenant(data.Uid.ValueString()), bucketName(state.Id.ValueString())))
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketLinkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketLinkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the bucket stays linked to the user, unless the previous owner is restored
+	if !data.RestorePreviousOwner.ValueBool() || data.PreviousOwner.ValueString() == "" {
+		return
+	}
+
+	err := linkBucket(ctx, r.client.Admin, data.Id.ValueString(), data.PreviousOwner.ValueString())
+	if err != nil && !errors.Is(err, admin.ErrNoSuchBucket) {
+		resp.Diagnostics.AddError("could not link bucket to previous owner", err.Error())
+		return
+	}
+}
+
+func (r *BucketLinkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, err := parseBucketImportId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("invalid import id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("restore_previous_owner_on_destroy"), false)...)
+}
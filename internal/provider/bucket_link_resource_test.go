@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBucketLinkResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketLinkResourceConfig("alice", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_link.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket_link.test", "uid", "alice"),
+					resource.TestCheckResourceAttr("rgw_bucket_link.test", "previous_owner", fakeRgwS3User),
+					resource.TestCheckResourceAttr("rgw_bucket_link.test", "restore_previous_owner_on_destroy", "true"),
+					testAccCheckBucketOwner(fake, "test", "alice"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "rgw_bucket_link.test",
+				ImportState:             true,
+				ImportStateId:           "test",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"previous_owner", "restore_previous_owner_on_destroy"},
+			},
+			// Update testing
+			{
+				Config: fake.providerConfig() + testAccBucketLinkResourceConfig("bob", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_link.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket_link.test", "uid", "bob"),
+					resource.TestCheckResourceAttr("rgw_bucket_link.test", "previous_owner", fakeRgwS3User),
+					testAccCheckBucketOwner(fake, "test", "bob"),
+				),
+			},
+			// Drift testing, the bucket is linked again to the user
+			{
+				PreConfig: func() {
+					fake.modifyFakeBucket("test", func(bucket *fakeBucket) {
+						bucket.info.Owner = "alice"
+					})
+				},
+				Config: fake.providerConfig() + testAccBucketLinkResourceConfig("bob", true),
+				Check:  testAccCheckBucketOwner(fake, "test", "bob"),
+			},
+			// Delete testing, the previous owner is restored
+			{
+				Config: fake.providerConfig() + testAccBucketLinkResourceConfig("", false),
+				Check:  testAccCheckBucketOwner(fake, "test", fakeRgwS3User),
+			},
+		},
+	})
+}
+
+func TestAccBucketLinkResource_tenant(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testAccCheckBucketOwner(fake, "test", fakeRgwS3User),
+			testAccCheckBucketDestroyed(fake, "tenant:test"),
+		),
+		Steps: []resource.TestStep{
+			// Create and Read testing, the bucket is moved into the tenant of the user
+			{
+				PreConfig: func() {
+					fake.addFakeBucket("test", fakeRgwS3User)
+				},
+				Config: fake.providerConfig() + testAccNamedUserConfig("owner", "tenant", "Owner") + `
+resource "rgw_bucket_link" "test" {
+  bucket = "test"
+  uid    = rgw_user.owner.id
+
+  restore_previous_owner_on_destroy = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_link.test", "id", "tenant:test"),
+					resource.TestCheckResourceAttr("rgw_bucket_link.test", "uid", "tenant$owner"),
+					resource.TestCheckResourceAttr("rgw_bucket_link.test", "previous_owner", fakeRgwS3User),
+					testAccCheckBucketOwner(fake, "tenant:test", "tenant$owner"),
+					testAccCheckBucketDestroyed(fake, "test"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "rgw_bucket_link.test",
+				ImportState:             true,
+				ImportStateId:           "tenant/test",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"bucket", "previous_owner", "restore_previous_owner_on_destroy"},
+			},
+		},
+	})
+}
+
+// testAccBucketLinkResourceConfig links the bucket to the user, or omits the
+// link if user is empty.
+func testAccBucketLinkResourceConfig(user string, restore bool) string {
+	config := testAccBucketConfig + testAccNamedUserConfig("alice", "", "Alice") + testAccNamedUserConfig("bob", "", "Bob")
+	if user == "" {
+		return config
+	}
+
+	return config + fmt.Sprintf(`
+resource "rgw_bucket_link" "test" {
+  bucket = rgw_bucket.test.id
+  uid    = rgw_user.%[1]s.id
+
+  restore_previous_owner_on_destroy = %[2]t
+}
+`, user, restore)
+}
@@ -0,0 +1,306 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithConfigure = &BucketNotificationResource{}
+var _ resource.ResourceWithImportState = &BucketNotificationResource{}
+
+func NewBucketNotificationResource() resource.Resource {
+	return &BucketNotificationResource{}
+}
+
+type BucketNotificationResource struct {
+	client *RgwClient
+}
+
+type BucketNotificationResourceModel struct {
+	Id     types.String                   `tfsdk:"id"`
+	Bucket types.String                   `tfsdk:"bucket"`
+	Topics []BucketNotificationTopicModel `tfsdk:"topic"`
+}
+
+type BucketNotificationTopicModel struct {
+	Id           types.String `tfsdk:"id"`
+	TopicArn     types.String `tfsdk:"topic_arn"`
+	Events       []string     `tfsdk:"events"`
+	FilterPrefix types.String `tfsdk:"filter_prefix"`
+	FilterSuffix types.String `tfsdk:"filter_suffix"`
+}
+
+func (r *BucketNotificationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_notification"
+}
+
+func (r *BucketNotificationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Bucket Notification Configuration in Ceph RGW. Manages all notifications of a bucket.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bucket": schema.StringAttribute{
+				MarkdownDescription: "Bucket Name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"topic": schema.SetNestedBlock{
+				MarkdownDescription: "Notification sent to a topic. The order of notifications is not significant.",
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Unique identifier of the notification",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.LengthBetween(1, 255),
+							},
+						},
+						"topic_arn": schema.StringAttribute{
+							MarkdownDescription: "ARN of the topic, e.g. the `arn` of a `rgw_topic`",
+							Required:            true,
+						},
+						"events": schema.SetAttribute{
+							MarkdownDescription: "Events triggering the notification, e.g. `s3:ObjectCreated:*` or `s3:ObjectRemoved:Delete`",
+							ElementType:         types.StringType,
+							Required:            true,
+							Validators: []validator.Set{
+								setvalidator.SizeAtLeast(1),
+								setvalidator.ValueStringsAre(stringvalidator.RegexMatches(regexp.MustCompile(`^s3:[A-Za-z]+(:[A-Za-z]+)*(:\*)?$`), "must be an event like `s3:ObjectCreated:*`")),
+							},
+						},
+						"filter_prefix": schema.StringAttribute{
+							MarkdownDescription: "Only send notifications for objects with keys starting with the prefix",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"filter_suffix": schema.StringAttribute{
+							MarkdownDescription: "Only send notifications for objects with keys ending with the suffix",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *BucketNotificationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *BucketNotificationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketNotificationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketNotificationConfiguration
+	if err := r.put(ctx, data.Bucket.ValueString(), data.Topics); err != nil {
+		resp.Diagnostics.AddError("could not create bucket notification configuration", err.Error())
+		return
+	}
+
+	// use bucket name as resource id
+	data.Id = types.StringValue(data.Bucket.ValueString())
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketNotificationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketNotificationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create GetBucketNotificationConfiguration Request
+	s3req := &s3.GetBucketNotificationConfigurationInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	}
+
+	s3res, err := r.client.S3.GetBucketNotificationConfiguration(ctx, s3req)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == "NoSuchBucket" {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("could not get bucket notification configuration", err.Error())
+		return
+	}
+
+	// all notifications were deleted
+	if len(s3res.TopicConfigurations) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.Topics = make([]BucketNotificationTopicModel, len(s3res.TopicConfigurations))
+	for i, topic := range s3res.TopicConfigurations {
+		data.Topics[i] = BucketNotificationTopicModel{
+			Id:           types.StringValue(aws.StringValue(topic.Id)),
+			TopicArn:     types.StringValue(aws.StringValue(topic.TopicArn)),
+			Events:       make([]string, len(topic.Events)),
+			FilterPrefix: types.StringNull(),
+			FilterSuffix: types.StringNull(),
+		}
+		for j, event := range topic.Events {
+			data.Topics[i].Events[j] = string(event)
+		}
+		if topic.Filter == nil || topic.Filter.Key == nil {
+			continue
+		}
+		for _, rule := range topic.Filter.Key.FilterRules {
+			// the names are case insensitive
+			switch strings.ToLower(string(rule.Name)) {
+			case "prefix":
+				data.Topics[i].FilterPrefix = types.StringValue(aws.StringValue(rule.Value))
+			case "suffix":
+				data.Topics[i].FilterSuffix = types.StringValue(aws.StringValue(rule.Value))
+			}
+		}
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketNotificationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketNotificationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketNotificationConfiguration
+	if err := r.put(ctx, data.Bucket.ValueString(), data.Topics); err != nil {
+		resp.Diagnostics.AddError("could not modify bucket notification configuration", err.Error())
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketNotificationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketNotificationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// an empty configuration deletes all notifications
+	err := r.put(ctx, data.Bucket.ValueString(), nil)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == "NoSuchBucket" {
+			return
+		}
+		resp.Diagnostics.AddError("could not delete bucket notification configuration", err.Error())
+		return
+	}
+}
+
+func (r *BucketNotificationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	bucket, err := parseBucketImportId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("invalid import id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), bucket)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), bucket)...)
+}
+
+func (r *BucketNotificationResource) put(ctx context.Context, bucket string, topics []BucketNotificationTopicModel) error {
+	configurations := make([]s3types.TopicConfiguration, len(topics))
+	for i, topic := range topics {
+		configurations[i] = s3types.TopicConfiguration{
+			Id:       aws.String(topic.Id.ValueString()),
+			TopicArn: aws.String(topic.TopicArn.ValueString()),
+			Events:   make([]s3types.Event, len(topic.Events)),
+		}
+		for j, event := range topic.Events {
+			configurations[i].Events[j] = s3types.Event(event)
+		}
+
+		var rules []s3types.FilterRule
+		if !topic.FilterPrefix.IsNull() {
+			rules = append(rules, s3types.FilterRule{Name: s3types.FilterRuleNamePrefix, Value: aws.String(topic.FilterPrefix.ValueString())})
+		}
+		if !topic.FilterSuffix.IsNull() {
+			rules = append(rules, s3types.FilterRule{Name: s3types.FilterRuleNameSuffix, Value: aws.String(topic.FilterSuffix.ValueString())})
+		}
+		if len(rules) > 0 {
+			configurations[i].Filter = &s3types.NotificationConfigurationFilter{
+				Key: &s3types.S3KeyFilter{FilterRules: rules},
+			}
+		}
+	}
+
+	_, err := r.client.S3.PutBucketNotificationConfiguration(ctx, &s3.PutBucketNotificationConfigurationInput{
+		Bucket: aws.String(bucket),
+		NotificationConfiguration: &s3types.NotificationConfiguration{
+			TopicConfigurations: configurations,
+		},
+	})
+	return err
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBucketNotificationResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketNotificationResourceConfig("s3:ObjectCreated:*"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_notification.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket_notification.test", "topic.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs("rgw_bucket_notification.test", "topic.*", map[string]string{
+						"id":            "uploads",
+						"topic_arn":     fakeTopicArn("test"),
+						"events.#":      "1",
+						"filter_prefix": "uploads/",
+						"filter_suffix": ".jpg",
+					}),
+					testAccCheckBucketSubresource(fake, "test", "notification", "s3:ObjectCreated:*"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_bucket_notification.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketNotificationResourceConfig("s3:ObjectRemoved:*"),
+				Check:  testAccCheckBucketSubresource(fake, "test", "notification", "s3:ObjectRemoved:*"),
+			},
+			// Drift testing, deleted configurations are restored
+			{
+				PreConfig: func() {
+					fake.modifyFakeBucket("test", func(bucket *fakeBucket) {
+						delete(bucket.subresources, "notification")
+					})
+				},
+				Config: fake.providerConfig() + testAccBucketNotificationResourceConfig("s3:ObjectRemoved:*"),
+				Check:  testAccCheckBucketSubresource(fake, "test", "notification", "s3:ObjectRemoved:*"),
+			},
+			// Delete testing, the bucket is kept
+			{
+				Config: fake.providerConfig() + testAccBucketConfig,
+				Check:  testAccCheckBucketSubresourceDeleted(fake, "test", "notification"),
+			},
+		},
+	})
+}
+
+func testAccBucketNotificationResourceConfig(event string) string {
+	return testAccBucketConfig + fmt.Sprintf(`
+resource "rgw_topic" "test" {
+  name          = "test"
+  push_endpoint = "http://example.com/notify"
+}
+
+resource "rgw_bucket_notification" "test" {
+  bucket = rgw_bucket.test.name
+
+  topic {
+    id            = "uploads"
+    topic_arn     = rgw_topic.test.arn
+    events        = [%[1]q]
+    filter_prefix = "uploads/"
+    filter_suffix = ".jpg"
+  }
+}
+`, event)
+}
@@ -8,15 +8,18 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.ResourceWithConfigure = &BucketPolicyResource{}
+var _ resource.ResourceWithImportState = &BucketPolicyResource{}
 
 func NewBucketPolicyResource() resource.Resource {
 	return &BucketPolicyResource{}
@@ -27,9 +30,9 @@ type BucketPolicyResource struct {
 }
 
 type BucketPolicyResourceModel struct {
-	Id     types.String `tfsdk:"id"`
-	Bucket types.String `tfsdk:"bucket"`
-	Policy types.String `tfsdk:"policy"`
+	Id     types.String        `tfsdk:"id"`
+	Bucket types.String        `tfsdk:"bucket"`
+	Policy PolicyDocumentValue `tfsdk:"policy"`
 }
 
 func (r *BucketPolicyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -55,8 +58,15 @@ func (r *BucketPolicyResource) Schema(ctx context.Context, req resource.SchemaRe
 				},
 			},
 			"policy": schema.StringAttribute{
-				MarkdownDescription: "Bucket Policy",
+				MarkdownDescription: "Bucket Policy in JSON format. Changes which do not alter the meaning of the policy, like formatting, key order or single values instead of lists, are ignored.",
 				Required:            true,
+				CustomType:          PolicyDocumentType{},
+				Validators: []validator.String{
+					policyDocumentValidator{},
+				},
+				PlanModifiers: []planmodifier.String{
+					policyDocumentModifier{},
+				},
 			},
 		},
 	}
@@ -128,6 +138,9 @@ func (r *BucketPolicyResource) Read(ctx context.Context, req resource.ReadReques
 		var ae smithy.APIError
 		if errors.As(err, &ae) {
 			switch ae.ErrorCode() {
+			case "NoSuchBucket", "NoSuchBucketPolicy":
+				resp.State.RemoveResource(ctx)
+				return
 			case "403":
 				resp.Diagnostics.AddError("acces denied", "If you are using an identity other than the root user of the Amazon Web Services account that owns the bucket, the calling identity must have the GetBucketPolicy permissions on the specified bucket and belong to the bucket owner's account in order to use this operation")
 				return
@@ -140,7 +153,11 @@ func (r *BucketPolicyResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
-	data.Policy = types.StringValue(*s3res.Policy)
+	// keep the policy of the state if only its formatting differs
+	policy := NewPolicyDocumentValue(aws.StringValue(s3res.Policy))
+	if equal, _ := data.Policy.StringSemanticEquals(ctx, policy); !equal {
+		data.Policy = policy
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -189,3 +206,14 @@ func (r *BucketPolicyResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 }
+
+func (r *BucketPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	bucket, err := parseBucketImportId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("invalid import id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), bucket)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), bucket)...)
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBucketPolicyResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketPolicyResourceConfig(`["s3:GetObject"]`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_policy.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket_policy.test", "bucket", "test"),
+					testAccCheckBucketSubresource(fake, "test", "policy", "s3:GetObject"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_bucket_policy.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Semantically equal policies do not cause changes
+			{
+				Config:   fake.providerConfig() + testAccBucketPolicyResourceConfig(`"s3:GetObject"`),
+				PlanOnly: true,
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketPolicyResourceConfig(`["s3:GetObject", "s3:ListBucket"]`),
+				Check:  testAccCheckBucketSubresource(fake, "test", "policy", "s3:ListBucket"),
+			},
+			// Drift testing, deleted policies are restored
+			{
+				PreConfig: func() {
+					fake.modifyFakeBucket("test", func(bucket *fakeBucket) {
+						delete(bucket.subresources, "policy")
+					})
+				},
+				Config: fake.providerConfig() + testAccBucketPolicyResourceConfig(`["s3:GetObject", "s3:ListBucket"]`),
+				Check:  testAccCheckBucketSubresource(fake, "test", "policy", "s3:ListBucket"),
+			},
+			// Delete testing, the bucket is kept
+			{
+				Config: fake.providerConfig() + testAccBucketConfig,
+				Check:  testAccCheckBucketSubresourceDeleted(fake, "test", "policy"),
+			},
+		},
+	})
+}
+
+func TestAccBucketPolicyResource_invalid(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fake.providerConfig() + testAccBucketPolicyResourceConfig(`"s3:GetObjekt"`),
+				ExpectError: regexp.MustCompile(`unknown action 's3:GetObjekt'`),
+			},
+		},
+	})
+}
+
+func testAccBucketPolicyResourceConfig(action string) string {
+	return testAccBucketConfig + fmt.Sprintf(`
+resource "rgw_bucket_policy" "test" {
+  bucket = rgw_bucket.test.name
+  policy = <<-EOF
+    {
+      "Version": "2012-10-17",
+      "Statement": [
+        {
+          "Effect": "Allow",
+          "Principal": {"AWS": ["arn:aws:iam:::user/reader"]},
+          "Action": %[1]s,
+          "Resource": ["arn:aws:s3:::test", "arn:aws:s3:::test/*"]
+        }
+      ]
+    }
+  EOF
+}
+`, action)
+}
@@ -0,0 +1,262 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithConfigure = &BucketQuotaResource{}
+var _ resource.ResourceWithImportState = &BucketQuotaResource{}
+var _ resource.ResourceWithModifyPlan = &BucketQuotaResource{}
+
+func NewBucketQuotaResource() resource.Resource {
+	return &BucketQuotaResource{}
+}
+
+type BucketQuotaResource struct {
+	client *RgwClient
+}
+
+type BucketQuotaResourceModel struct {
+	Id         types.String `tfsdk:"id"`
+	Bucket     types.String `tfsdk:"bucket"`
+	Enabled    types.Bool   `tfsdk:"enabled"`
+	MaxSize    types.Int64  `tfsdk:"max_size"`
+	MaxObjects types.Int64  `tfsdk:"max_objects"`
+}
+
+func (r *BucketQuotaResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_quota"
+}
+
+func (r *BucketQuotaResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Quota of a single bucket in Ceph RGW. The quota is disabled and reset to unlimited on deletion.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bucket": schema.StringAttribute{
+				MarkdownDescription: "Bucket Name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Specify whether the quota is enforced.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolDefaultModifier{true},
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"max_size": schema.Int64Attribute{
+				MarkdownDescription: "Maximum size of all objects in the bucket in bytes. `-1` means unlimited.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64DefaultModifier{quotaUnlimited},
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(quotaUnlimited),
+				},
+			},
+			"max_objects": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of objects in the bucket. `-1` means unlimited.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64DefaultModifier{quotaUnlimited},
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(quotaUnlimited),
+				},
+			},
+		},
+	}
+}
+
+func (r *BucketQuotaResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config, plan *BucketQuotaResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// values which are not configured are reset to their defaults, so that
+	// quotas changed outside of terraform are restored
+	if config.Enabled.IsNull() {
+		plan.Enabled = types.BoolValue(true)
+	}
+	if config.MaxSize.IsNull() {
+		plan.MaxSize = types.Int64Value(quotaUnlimited)
+	}
+	if config.MaxObjects.IsNull() {
+		plan.MaxObjects = types.Int64Value(quotaUnlimited)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *BucketQuotaResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *BucketQuotaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketQuotaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// set quota
+	if err := r.setQuota(ctx, data.Bucket.ValueString(), data.Enabled.ValueBool(), data.MaxSize.ValueInt64(), data.MaxObjects.ValueInt64()); err != nil {
+		resp.Diagnostics.AddError("could not set bucket quota", err.Error())
+		return
+	}
+
+	// use bucket name as resource id
+	data.Id = types.StringValue(data.Bucket.ValueString())
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketQuotaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketQuotaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// get bucket info including quota
+	info, err := r.client.Admin.GetBucketInfo(ctx, admin.Bucket{
+		Bucket: adminBucketName(data.Bucket.ValueString()),
+	})
+	if err != nil {
+		if errors.Is(err, admin.ErrNoSuchBucket) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("could not get bucket quota", err.Error())
+		return
+	}
+
+	quota := info.BucketQuota
+	data.Enabled = types.BoolValue(quota.Enabled != nil && *quota.Enabled)
+	data.MaxSize = types.Int64Value(quotaValue(quota.MaxSize))
+	data.MaxObjects = types.Int64Value(quotaValue(quota.MaxObjects))
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketQuotaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketQuotaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// set quota
+	if err := r.setQuota(ctx, data.Bucket.ValueString(), data.Enabled.ValueBool(), data.MaxSize.ValueInt64(), data.MaxObjects.ValueInt64()); err != nil {
+		resp.Diagnostics.AddError("could not modify bucket quota", err.Error())
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketQuotaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketQuotaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// reset quota
+	err := r.setQuota(ctx, data.Bucket.ValueString(), false, quotaUnlimited, quotaUnlimited)
+	if err != nil && !errors.Is(err, admin.ErrNoSuchBucket) {
+		resp.Diagnostics.AddError("could not reset bucket quota", err.Error())
+		return
+	}
+}
+
+func (r *BucketQuotaResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	bucket, err := parseBucketImportId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("invalid import id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), bucket)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), bucket)...)
+}
+
+// setQuota sets the quota of a bucket, the api requires the bucket owner.
+func (r *BucketQuotaResource) setQuota(ctx context.Context, bucket string, enabled bool, maxSize int64, maxObjects int64) error {
+	info, err := r.client.Admin.GetBucketInfo(ctx, admin.Bucket{
+		Bucket: adminBucketName(bucket),
+	})
+	if err != nil {
+		return err
+	}
+
+	// strip tenant prefix, the tenant is part of the owner uid
+	splittedBucket := strings.SplitN(bucket, ":", 2)
+
+	quota := quotaSpec(info.Owner, enabled, maxSize, maxObjects)
+	quota.Bucket = splittedBucket[len(splittedBucket)-1]
+
+	return r.client.Admin.SetIndividualBucketQuota(ctx, quota)
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccBucketQuotaResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketQuotaResourceConfig(1024),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_quota.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket_quota.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("rgw_bucket_quota.test", "max_size", "1024"),
+					resource.TestCheckResourceAttr("rgw_bucket_quota.test", "max_objects", "-1"),
+					testAccCheckBucketQuota(fake, "test", true, 1024),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_bucket_quota.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketQuotaResourceConfig(2048),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_quota.test", "max_size", "2048"),
+					testAccCheckBucketQuota(fake, "test", true, 2048),
+				),
+			},
+			// Drift testing, quotas changed outside of terraform are restored
+			{
+				PreConfig: func() {
+					fake.modifyFakeBucket("test", func(bucket *fakeBucket) {
+						enabled := false
+						maxSize := int64(1)
+						bucket.info.BucketQuota.Enabled = &enabled
+						bucket.info.BucketQuota.MaxSize = &maxSize
+					})
+				},
+				Config: fake.providerConfig() + testAccBucketQuotaResourceConfig(2048),
+				Check:  testAccCheckBucketQuota(fake, "test", true, 2048),
+			},
+			// Delete testing, the quota is reset
+			{
+				Config: fake.providerConfig() + testAccBucketConfig,
+				Check:  testAccCheckBucketQuota(fake, "test", false, quotaUnlimited),
+			},
+		},
+	})
+}
+
+func testAccBucketQuotaResourceConfig(maxSize int) string {
+	return testAccBucketConfig + fmt.Sprintf(`
+resource "rgw_bucket_quota" "test" {
+  bucket   = rgw_bucket.test.name
+  max_size = %[1]d
+}
+`, maxSize)
+}
+
+func testAccCheckBucketQuota(fake *fakeRgw, bucket string, enabled bool, maxSize int64) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		b := fake.bucket(bucket)
+		if b == nil {
+			return fmt.Errorf("bucket '%s' does not exist", bucket)
+		}
+		return checkFakeQuota(b.info.BucketQuota, enabled, maxSize)
+	}
+}
@@ -0,0 +1,380 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithConfigure = &BucketReplicationConfigurationResource{}
+var _ resource.ResourceWithImportState = &BucketReplicationConfigurationResource{}
+
+// prefix of bucket ARNs accepted as destination bucket
+const s3BucketArnPrefix = "arn:aws:s3:::"
+
+func NewBucketReplicationConfigurationResource() resource.Resource {
+	return &BucketReplicationConfigurationResource{}
+}
+
+type BucketReplicationConfigurationResource struct {
+	client *RgwClient
+}
+
+type BucketReplicationConfigurationResourceModel struct {
+	Id     types.String                 `tfsdk:"id"`
+	Bucket types.String                 `tfsdk:"bucket"`
+	Rules  []BucketReplicationRuleModel `tfsdk:"rule"`
+}
+
+type BucketReplicationRuleModel struct {
+	Id                      types.String      `tfsdk:"id"`
+	Status                  types.String      `tfsdk:"status"`
+	Priority                types.Int64       `tfsdk:"priority"`
+	Prefix                  types.String      `tfsdk:"prefix"`
+	Tags                    map[string]string `tfsdk:"tags"`
+	DestinationBucket       types.String      `tfsdk:"destination_bucket"`
+	DeleteMarkerReplication types.Bool        `tfsdk:"delete_marker_replication"`
+}
+
+func (r *BucketReplicationConfigurationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_replication_configuration"
+}
+
+func (r *BucketReplicationConfigurationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Bucket Replication Configuration in Ceph RGW. RGW converts the rules into a multisite sync policy of the bucket, so a multisite setup with a zonegroup sync policy allowing bucket sync is required.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bucket": schema.StringAttribute{
+				MarkdownDescription: "Bucket Name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"rule": schema.ListNestedBlock{
+				MarkdownDescription: "Replication rule.",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Unique identifier of the rule. RGW generates an id if it is not set, generated ids are ignored.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.LengthBetween(1, 255),
+							},
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "Whether the rule is applied. One of `Enabled` or `Disabled`.",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(string(s3types.ReplicationRuleStatusEnabled), string(s3types.ReplicationRuleStatusDisabled)),
+							},
+						},
+						"priority": schema.Int64Attribute{
+							MarkdownDescription: "Priority of the rule if objects match multiple rules, rules with higher priority take precedence",
+							Optional:            true,
+							Validators: []validator.Int64{
+								int64validator.AtLeast(1),
+							},
+						},
+						"prefix": schema.StringAttribute{
+							MarkdownDescription: "Only replicate objects with this key prefix",
+							Optional:            true,
+						},
+						"tags": schema.MapAttribute{
+							MarkdownDescription: "Only replicate objects having all of these tags",
+							ElementType:         types.StringType,
+							Optional:            true,
+						},
+						"destination_bucket": schema.StringAttribute{
+							MarkdownDescription: "Name of the destination bucket (`tenant:bucket` for buckets of a tenant), may be given as ARN `arn:aws:s3:::bucket`",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"delete_marker_replication": schema.BoolAttribute{
+							MarkdownDescription: "Whether delete markers are replicated. Defaults to `false`.",
+							Optional:            true,
+							Computed:            true,
+							PlanModifiers: []planmodifier.Bool{
+								boolDefaultModifier{false},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *BucketReplicationConfigurationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *BucketReplicationConfigurationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketReplicationConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketReplication
+	if err := r.put(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not create bucket replication configuration", err.Error())
+		return
+	}
+
+	// use bucket name as resource id
+	data.Id = types.StringValue(data.Bucket.ValueString())
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketReplicationConfigurationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketReplicationConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create GetBucketReplication Request
+	s3req := &s3.GetBucketReplicationInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	}
+
+	s3res, err := r.client.S3.GetBucketReplication(ctx, s3req)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) {
+			switch ae.ErrorCode() {
+			case "NoSuchBucket", "ReplicationConfigurationNotFoundError":
+				resp.State.RemoveResource(ctx)
+				return
+			}
+		}
+		resp.Diagnostics.AddError("could not get bucket replication configuration", err.Error())
+		return
+	}
+
+	if s3res.ReplicationConfiguration == nil || len(s3res.ReplicationConfiguration.Rules) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	rules := make([]BucketReplicationRuleModel, len(s3res.ReplicationConfiguration.Rules))
+	for i, rule := range s3res.ReplicationConfiguration.Rules {
+		rules[i] = flattenReplicationRule(rule)
+
+		// rules are compared by position with the prior state, ids generated
+		// by RGW for rules without id and the form of the destination bucket
+		// are kept as configured
+		if i < len(data.Rules) {
+			if data.Rules[i].Id.IsNull() {
+				rules[i].Id = types.StringNull()
+			}
+			prior := data.Rules[i].DestinationBucket.ValueString()
+			if strings.TrimPrefix(prior, s3BucketArnPrefix) == strings.TrimPrefix(rules[i].DestinationBucket.ValueString(), s3BucketArnPrefix) {
+				rules[i].DestinationBucket = data.Rules[i].DestinationBucket
+			}
+		}
+	}
+	data.Rules = rules
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketReplicationConfigurationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketReplicationConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketReplication
+	if err := r.put(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not modify bucket replication configuration", err.Error())
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketReplicationConfigurationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketReplicationConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	s3req := &s3.DeleteBucketReplicationInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	}
+
+	_, err := r.client.S3.DeleteBucketReplication(ctx, s3req)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == "NoSuchBucket" {
+			return
+		}
+		resp.Diagnostics.AddError("could not delete bucket replication configuration", err.Error())
+		return
+	}
+}
+
+func (r *BucketReplicationConfigurationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	bucket, err := parseBucketImportId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("invalid import id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), bucket)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), bucket)...)
+}
+
+func (r *BucketReplicationConfigurationResource) put(ctx context.Context, data *BucketReplicationConfigurationResourceModel) error {
+	rules := make([]s3types.ReplicationRule, len(data.Rules))
+	for i, rule := range data.Rules {
+		rules[i] = expandReplicationRule(rule)
+	}
+
+	_, err := r.client.S3.PutBucketReplication(ctx, &s3.PutBucketReplicationInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+		ReplicationConfiguration: &s3types.ReplicationConfiguration{
+			// RGW ignores the role, but the sdk requires it
+			Role:  aws.String(""),
+			Rules: rules,
+		},
+	})
+	return err
+}
+
+func expandReplicationRule(rule BucketReplicationRuleModel) s3types.ReplicationRule {
+	res := s3types.ReplicationRule{
+		Status:      s3types.ReplicationRuleStatus(rule.Status.ValueString()),
+		Priority:    int32(rule.Priority.ValueInt64()),
+		Destination: &s3types.Destination{Bucket: aws.String(rule.DestinationBucket.ValueString())},
+		DeleteMarkerReplication: &s3types.DeleteMarkerReplication{
+			Status: s3types.DeleteMarkerReplicationStatusDisabled,
+		},
+	}
+	if !rule.Id.IsNull() {
+		res.ID = aws.String(rule.Id.ValueString())
+	}
+	if rule.DeleteMarkerReplication.ValueBool() {
+		res.DeleteMarkerReplication.Status = s3types.DeleteMarkerReplicationStatusEnabled
+	}
+
+	// filter
+	if len(rule.Tags) == 0 {
+		res.Filter = &s3types.ReplicationRuleFilterMemberPrefix{Value: rule.Prefix.ValueString()}
+	} else if len(rule.Tags) == 1 && rule.Prefix.IsNull() {
+		for k, v := range rule.Tags {
+			res.Filter = &s3types.ReplicationRuleFilterMemberTag{Value: s3types.Tag{Key: aws.String(k), Value: aws.String(v)}}
+		}
+	} else {
+		and := s3types.ReplicationRuleAndOperator{}
+		if !rule.Prefix.IsNull() {
+			and.Prefix = aws.String(rule.Prefix.ValueString())
+		}
+		for k, v := range rule.Tags {
+			and.Tags = append(and.Tags, s3types.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+		res.Filter = &s3types.ReplicationRuleFilterMemberAnd{Value: and}
+	}
+
+	return res
+}
+
+// flattenReplicationRule converts a rule returned by the api into the model.
+// Zero values are mapped to null, so rules read back match the configuration.
+func flattenReplicationRule(rule s3types.ReplicationRule) BucketReplicationRuleModel {
+	res := BucketReplicationRuleModel{
+		Id:                      stringOrNull(rule.ID),
+		Status:                  types.StringValue(string(rule.Status)),
+		Priority:                int64OrNull(int64(rule.Priority)),
+		Prefix:                  types.StringNull(),
+		DestinationBucket:       types.StringNull(),
+		DeleteMarkerReplication: types.BoolValue(false),
+	}
+	if rule.Destination != nil {
+		res.DestinationBucket = stringOrNull(rule.Destination.Bucket)
+	}
+	if rule.DeleteMarkerReplication != nil {
+		res.DeleteMarkerReplication = types.BoolValue(rule.DeleteMarkerReplication.Status == s3types.DeleteMarkerReplicationStatusEnabled)
+	}
+
+	// filter
+	prefix := aws.StringValue(rule.Prefix)
+	switch f := rule.Filter.(type) {
+	case *s3types.ReplicationRuleFilterMemberPrefix:
+		prefix = f.Value
+	case *s3types.ReplicationRuleFilterMemberTag:
+		res.Tags = map[string]string{aws.StringValue(f.Value.Key): aws.StringValue(f.Value.Value)}
+	case *s3types.ReplicationRuleFilterMemberAnd:
+		prefix = aws.StringValue(f.Value.Prefix)
+		if len(f.Value.Tags) > 0 {
+			res.Tags = make(map[string]string, len(f.Value.Tags))
+			for _, t := range f.Value.Tags {
+				res.Tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+			}
+		}
+	}
+	if prefix != "" {
+		res.Prefix = types.StringValue(prefix)
+	}
+
+	return res
+}
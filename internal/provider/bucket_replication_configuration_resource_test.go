@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBucketReplicationConfigurationResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			// Create and Read testing, the id generated by RGW is ignored
+			{
+				Config: fake.providerConfig() + testAccBucketReplicationConfigurationResourceConfig(`
+  rule {
+    status             = "Enabled"
+    prefix             = "logs/"
+    tags               = { team = "infra" }
+    destination_bucket = "arn:aws:s3:::backup"
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_replication_configuration.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket_replication_configuration.test", "rule.#", "1"),
+					resource.TestCheckNoResourceAttr("rgw_bucket_replication_configuration.test", "rule.0.id"),
+					resource.TestCheckResourceAttr("rgw_bucket_replication_configuration.test", "rule.0.prefix", "logs/"),
+					resource.TestCheckResourceAttr("rgw_bucket_replication_configuration.test", "rule.0.tags.team", "infra"),
+					resource.TestCheckResourceAttr("rgw_bucket_replication_configuration.test", "rule.0.destination_bucket", "arn:aws:s3:::backup"),
+					resource.TestCheckResourceAttr("rgw_bucket_replication_configuration.test", "rule.0.delete_marker_replication", "false"),
+					testAccCheckBucketSubresource(fake, "test", "replication", "generated-1"),
+				),
+			},
+			// ImportState testing, imported rules keep the generated id
+			{
+				ResourceName:            "rgw_bucket_replication_configuration.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"rule.0.id"},
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketReplicationConfigurationResourceConfig(`
+  rule {
+    status             = "Disabled"
+    priority           = 1
+    prefix             = "logs/"
+    destination_bucket = "backup"
+  }
+
+  rule {
+    id                        = "archive"
+    status                    = "Enabled"
+    priority                  = 2
+    destination_bucket        = "archive"
+    delete_marker_replication = true
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_replication_configuration.test", "rule.#", "2"),
+					resource.TestCheckNoResourceAttr("rgw_bucket_replication_configuration.test", "rule.0.id"),
+					resource.TestCheckResourceAttr("rgw_bucket_replication_configuration.test", "rule.0.status", "Disabled"),
+					resource.TestCheckNoResourceAttr("rgw_bucket_replication_configuration.test", "rule.0.tags.%"),
+					resource.TestCheckResourceAttr("rgw_bucket_replication_configuration.test", "rule.1.id", "archive"),
+					resource.TestCheckResourceAttr("rgw_bucket_replication_configuration.test", "rule.1.delete_marker_replication", "true"),
+					testAccCheckBucketSubresource(fake, "test", "replication", "<ID>archive</ID>"),
+				),
+			},
+			// Drift testing, deleted configurations are restored
+			{
+				PreConfig: func() {
+					fake.modifyFakeBucket("test", func(bucket *fakeBucket) {
+						delete(bucket.subresources, "replication")
+					})
+				},
+				Config: fake.providerConfig() + testAccBucketReplicationConfigurationResourceConfig(`
+  rule {
+    status             = "Disabled"
+    priority           = 1
+    prefix             = "logs/"
+    destination_bucket = "backup"
+  }
+
+  rule {
+    id                        = "archive"
+    status                    = "Enabled"
+    priority                  = 2
+    destination_bucket        = "archive"
+    delete_marker_replication = true
+  }
+`),
+				Check: testAccCheckBucketSubresource(fake, "test", "replication", "<ID>archive</ID>"),
+			},
+			// Delete testing, the bucket is kept
+			{
+				Config: fake.providerConfig() + testAccBucketConfig,
+				Check:  testAccCheckBucketSubresourceDeleted(fake, "test", "replication"),
+			},
+		},
+	})
+}
+
+func testAccBucketReplicationConfigurationResourceConfig(rules string) string {
+	return testAccBucketConfig + fmt.Sprintf(`
+resource "rgw_bucket_replication_configuration" "test" {
+  bucket = rgw_bucket.test.name
+  %s
+}
+`, rules)
+}
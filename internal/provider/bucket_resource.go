@@ -4,20 +4,39 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/smithy-go"
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// maximum number of keys per DeleteObjects request.
+const deleteObjectsBatchSize = 1000
+
+// number of DeleteObjects/AbortMultipartUpload requests running in parallel
+// while emptying a bucket.
+const forceDestroyConcurrency = 4
+
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.ResourceWithConfigure = &BucketResource{}
+var _ resource.ResourceWithImportState = &BucketResource{}
+var _ resource.ResourceWithValidateConfig = &BucketResource{}
+var _ resource.ResourceWithModifyPlan = &BucketResource{}
 
 func NewBucketResource() resource.Resource {
 	return &BucketResource{}
@@ -28,8 +47,23 @@ type BucketResource struct {
 }
 
 type BucketResourceModel struct {
-	Id   types.String `tfsdk:"id"`
-	Name types.String `tfsdk:"name"`
+	Id                         types.String                    `tfsdk:"id"`
+	Name                       types.String                    `tfsdk:"name"`
+	Tenant                     types.String                    `tfsdk:"tenant"`
+	Owner                      types.String                    `tfsdk:"owner"`
+	ForceDestroy               types.Bool                      `tfsdk:"force_destroy"`
+	Versioning                 types.String                    `tfsdk:"versioning"`
+	ObjectLockEnabled          types.Bool                      `tfsdk:"object_lock_enabled"`
+	ObjectLockDefaultRetention *BucketObjectLockRetentionModel `tfsdk:"object_lock_default_retention"`
+	Tags                       map[string]string               `tfsdk:"tags"`
+	PlacementTarget            types.String                    `tfsdk:"placement_target"`
+	StorageClass               types.String                    `tfsdk:"storage_class"`
+}
+
+type BucketObjectLockRetentionModel struct {
+	Mode  types.String `tfsdk:"mode"`
+	Days  types.Int64  `tfsdk:"days"`
+	Years types.Int64  `tfsdk:"years"`
 }
 
 func (r *BucketResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -43,7 +77,7 @@ func (r *BucketResource) Schema(ctx context.Context, req resource.SchemaRequest,
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "Example identifier",
+				MarkdownDescription: "Bucket name as used in S3 requests, including the tenant (`tenant:bucket`) if the bucket belongs to a tenant",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -55,10 +89,169 @@ func (r *BucketResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"tenant": schema.StringAttribute{
+				MarkdownDescription: "Tenant of the bucket. Defaults to the tenant of `owner` or of the S3 credentials. Changing the tenant without changing `owner` recreates the bucket.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"owner": schema.StringAttribute{
+				MarkdownDescription: "Owner of the bucket including the tenant (`tenant$user`). The bucket is linked to the owner via the admin api, which moves it into the tenant of the owner. Defaults to the user of the S3 credentials. Managing buckets of other users requires S3 credentials of a system user.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"force_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Delete all objects, object versions, delete markers and unfinished multipart uploads before deleting the bucket. These objects are not recoverable.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolDefaultModifier{false},
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"versioning": schema.StringAttribute{
+				MarkdownDescription: "Versioning state of the bucket. One of `Enabled` or `Suspended`. Versioning can not be disabled once it was enabled. If not set, versioning is not managed and the state of the bucket is only read.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(s3types.BucketVersioningStatusEnabled), string(s3types.BucketVersioningStatusSuspended)),
+				},
+			},
+			"object_lock_enabled": schema.BoolAttribute{
+				MarkdownDescription: "Enable object lock for the bucket. Object lock can only be enabled on bucket creation and implies versioning.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolDefaultModifier{false},
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"object_lock_default_retention": schema.SingleNestedAttribute{
+				MarkdownDescription: "Default retention applied to new objects. Requires `object_lock_enabled`.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"mode": schema.StringAttribute{
+						MarkdownDescription: "Retention mode. One of `GOVERNANCE` or `COMPLIANCE`.",
+						Required:            true,
+						Validators: []validator.String{
+							stringvalidator.OneOf(string(s3types.ObjectLockRetentionModeGovernance), string(s3types.ObjectLockRetentionModeCompliance)),
+						},
+					},
+					"days": schema.Int64Attribute{
+						MarkdownDescription: "Retention period in days. Conflicts with `years`.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+							int64validator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("years")),
+						},
+					},
+					"years": schema.Int64Attribute{
+						MarkdownDescription: "Retention period in years. Conflicts with `days`.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+				},
+			},
+			"tags": schema.MapAttribute{
+				MarkdownDescription: "Tags of the bucket",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"placement_target": schema.StringAttribute{
+				MarkdownDescription: "Placement target of the bucket, sent as `LocationConstraint` on creation. Defaults to the placement target of the zonegroup or user.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"storage_class": schema.StringAttribute{
+				MarkdownDescription: "Default storage class of the bucket within the placement target, sent as `LocationConstraint` on creation. Requires `placement_target`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("placement_target")),
+				},
+			},
 		},
 	}
 }
 
+func (r *BucketResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *BucketResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.ObjectLockDefaultRetention != nil && !data.ObjectLockEnabled.IsUnknown() && !data.ObjectLockEnabled.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("object_lock_default_retention"), "object lock not enabled", "a default retention can only be configured if object_lock_enabled is set to true")
+	}
+
+	if !data.Tenant.IsNull() && !data.Tenant.IsUnknown() && !data.Owner.IsNull() && !data.Owner.IsUnknown() {
+		if tenant := userTenant(data.Owner.ValueString()); tenant != data.Tenant.ValueString() {
+			resp.Diagnostics.AddAttributeError(path.Root("owner"), "owner of another tenant", fmt.Sprintf("the owner must belong to the tenant of the bucket '%s', got tenant '%s'", data.Tenant.ValueString(), tenant))
+		}
+	}
+}
+
+func (r *BucketResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config, plan, state *BucketResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the bucket moves into the tenant of the owner
+	if config.Tenant.IsNull() && !config.Owner.IsNull() {
+		if config.Owner.IsUnknown() {
+			plan.Tenant = types.StringUnknown()
+		} else {
+			plan.Tenant = types.StringValue(userTenant(config.Owner.ValueString()))
+		}
+	}
+
+	// nothing else to do on create
+	if state == nil {
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		return
+	}
+
+	if !plan.Tenant.Equal(state.Tenant) {
+		if config.Owner.IsNull() {
+			// there is no owner to link the bucket to in the new tenant
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("tenant"))
+		} else {
+			// the id changes when the bucket is moved into another tenant
+			plan.Id = types.StringUnknown()
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
 func (r *BucketResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -87,9 +280,29 @@ func (r *BucketResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	// Create the bucket with the tenant of the S3 credentials, if it is linked
+	// to the owner afterwards
+	bucket := data.Name.ValueString()
+	if data.Owner.IsUnknown() || data.Owner.IsNull() {
+		bucket = bucketId(data.Tenant.ValueString(), data.Name.ValueString())
+	}
+
 	// Configure CreateBucketInput
 	s3req := &s3.CreateBucketInput{
-		Bucket: aws.String(data.Name.ValueString()),
+		Bucket:                     aws.String(bucket),
+		ObjectLockEnabledForBucket: data.ObjectLockEnabled.ValueBool(),
+	}
+
+	// set placement via location constraint "<zonegroup>:<placement-target>[/<storage-class>]",
+	// an empty zonegroup refers to the zonegroup of the endpoint
+	if !data.PlacementTarget.IsUnknown() && !data.PlacementTarget.IsNull() {
+		placement := data.PlacementTarget.ValueString()
+		if !data.StorageClass.IsUnknown() && !data.StorageClass.IsNull() {
+			placement = fmt.Sprintf("%s/%s", placement, data.StorageClass.ValueString())
+		}
+		s3req.CreateBucketConfiguration = &s3types.CreateBucketConfiguration{
+			LocationConstraint: s3types.BucketLocationConstraint(":" + placement),
+		}
 	}
 
 	tflog.Info(ctx, fmt.Sprintf("create bucket %s", *s3req.Bucket))
@@ -102,6 +315,77 @@ func (r *BucketResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	data.Id = types.StringValue(*s3req.Bucket)
 
+	// save the bucket id before configuring the bucket, so a failure does not leave an untracked bucket
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// buckets without tenant are created in the tenant of the S3 credentials,
+	// the admin api and the link to the owner require the tenant in the id
+	if bucketTenant(bucket) == "" {
+		tenant, err := bucketOwnerTenant(ctx, r.client.S3, bucket)
+		if err != nil {
+			resp.Diagnostics.AddError("could not get tenant of bucket", err.Error())
+			return
+		}
+		data.Id = types.StringValue(bucketId(tenant, data.Name.ValueString()))
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// configure versioning
+	if !data.Versioning.IsUnknown() && !data.Versioning.IsNull() {
+		if err := putBucketVersioning(ctx, r.client.S3, data.Id.ValueString(), data.Versioning.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("versioning"), "could not configure bucket versioning", err.Error())
+			return
+		}
+	}
+
+	// configure object lock default retention
+	if data.ObjectLockDefaultRetention != nil {
+		if err := putObjectLockConfiguration(ctx, r.client.S3, data.Id.ValueString(), data.ObjectLockDefaultRetention); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("object_lock_default_retention"), "could not configure object lock", err.Error())
+			return
+		}
+	}
+
+	// configure tags
+	if len(data.Tags) > 0 {
+		if err := putBucketTagging(ctx, r.client.S3, data.Id.ValueString(), data.Tags); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("tags"), "could not configure bucket tags", err.Error())
+			return
+		}
+	}
+
+	// link bucket to the owner, configuration of the bucket requires access to it
+	if !data.Owner.IsUnknown() && !data.Owner.IsNull() {
+		if err := linkBucket(ctx, r.client.Admin, data.Id.ValueString(), data.Owner.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("owner"), "could not link bucket to owner", err.Error())
+			return
+		}
+		data.Id = types.StringValue(bucketId(userTenant(data.Owner.ValueString()), data.Name.ValueString()))
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	}
+
+	// read versioning if not managed, object lock implies versioning
+	if data.Versioning.IsUnknown() {
+		versioning, err := getBucketVersioning(ctx, r.client.S3, data.Id.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("could not get bucket versioning", err.Error())
+			return
+		}
+		data.Versioning = versioning
+	}
+
+	// read tenant, owner and placement
+	if err := r.readBucketInfo(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not get bucket info", err.Error())
+		return
+	}
+
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
 	tflog.Trace(ctx, "created a resource")
@@ -128,10 +412,11 @@ func (r *BucketResource) Read(ctx context.Context, req resource.ReadRequest, res
 		var ae smithy.APIError
 		if errors.As(err, &ae) {
 			switch ae.ErrorCode() {
-			case "404":
+			// responses to head requests have no body, the code is derived from the status
+			case "404", "NotFound":
 				resp.State.RemoveResource(ctx)
 				return
-			case "403":
+			case "403", "Forbidden":
 				resp.Diagnostics.AddError("no permission to head bucket", err.Error())
 				return
 			}
@@ -140,21 +425,141 @@ func (r *BucketResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
-	data.Name = types.StringValue(*s3req.Bucket)
+	// strip tenant prefix from bucket name
+	splittedId := strings.SplitN(data.Id.ValueString(), ":", 2)
+	data.Name = types.StringValue(splittedId[len(splittedId)-1])
+
+	// update versioning
+	data.Versioning, err = getBucketVersioning(ctx, r.client.S3, data.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("could not get bucket versioning", err.Error())
+		return
+	}
+
+	// update object lock
+	objectLock, err := r.client.S3.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{
+		Bucket: aws.String(data.Id.ValueString()),
+	})
+	if err != nil {
+		var ae smithy.APIError
+		if !errors.As(err, &ae) || ae.ErrorCode() != "ObjectLockConfigurationNotFoundError" {
+			resp.Diagnostics.AddError("could not get object lock configuration", err.Error())
+			return
+		}
+		data.ObjectLockEnabled = types.BoolValue(false)
+		data.ObjectLockDefaultRetention = nil
+	} else {
+		config := objectLock.ObjectLockConfiguration
+		data.ObjectLockEnabled = types.BoolValue(config != nil && config.ObjectLockEnabled == s3types.ObjectLockEnabledEnabled)
+		data.ObjectLockDefaultRetention = nil
+		if config != nil && config.Rule != nil && config.Rule.DefaultRetention != nil {
+			retention := config.Rule.DefaultRetention
+			data.ObjectLockDefaultRetention = &BucketObjectLockRetentionModel{
+				Mode:  types.StringValue(string(retention.Mode)),
+				Days:  types.Int64Null(),
+				Years: types.Int64Null(),
+			}
+			if retention.Days > 0 {
+				data.ObjectLockDefaultRetention.Days = types.Int64Value(int64(retention.Days))
+			}
+			if retention.Years > 0 {
+				data.ObjectLockDefaultRetention.Years = types.Int64Value(int64(retention.Years))
+			}
+		}
+	}
+
+	// update tags
+	tags, err := getBucketTagging(ctx, r.client.S3, data.Id.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("could not get bucket tags", err.Error())
+		return
+	}
+	switch {
+	case len(tags) > 0:
+		data.Tags = tags
+	case data.Tags != nil:
+		// keep configured empty tags, RGW does not distinguish them from no tags
+		data.Tags = map[string]string{}
+	}
+
+	// update tenant, owner and placement
+	if err := r.readBucketInfo(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not get bucket info", err.Error())
+		return
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *BucketResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Read Terraform plan data into the model
-	var data *BucketResourceModel
+	// Read Terraform plan and prior state data into the models
+	var data, state *BucketResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Currently there is nothing to update in place
+	// link bucket to the new owner, which moves it into the tenant of the owner
+	data.Id = state.Id
+	if !data.Owner.IsUnknown() && !data.Owner.Equal(state.Owner) {
+		if err := linkBucket(ctx, r.client.Admin, state.Id.ValueString(), data.Owner.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("owner"), "could not link bucket to owner", err.Error())
+			return
+		}
+		data.Id = types.StringValue(bucketId(userTenant(data.Owner.ValueString()), data.Name.ValueString()))
+
+		// save the new id, so a failure does not leave an untracked bucket
+		state.Id = data.Id
+		state.Owner = data.Owner
+		state.Tenant = data.Tenant
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// update versioning, the state is kept if versioning is not managed
+	if data.Versioning.IsUnknown() {
+		data.Versioning = state.Versioning
+	}
+	if !data.Versioning.IsNull() && !data.Versioning.Equal(state.Versioning) {
+		if err := putBucketVersioning(ctx, r.client.S3, data.Id.ValueString(), data.Versioning.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("versioning"), "could not configure bucket versioning", err.Error())
+			return
+		}
+	}
+
+	// update object lock default retention
+	if !objectLockRetentionEqual(data.ObjectLockDefaultRetention, state.ObjectLockDefaultRetention) {
+		if err := putObjectLockConfiguration(ctx, r.client.S3, data.Id.ValueString(), data.ObjectLockDefaultRetention); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("object_lock_default_retention"), "could not configure object lock", err.Error())
+			return
+		}
+	}
+
+	// update tags
+	if !tagsEqual(data.Tags, state.Tags) {
+		var err error
+		if len(data.Tags) > 0 {
+			err = putBucketTagging(ctx, r.client.S3, data.Id.ValueString(), data.Tags)
+		} else {
+			_, err = r.client.S3.DeleteBucketTagging(ctx, &s3.DeleteBucketTaggingInput{
+				Bucket: aws.String(data.Id.ValueString()),
+			})
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("tags"), "could not configure bucket tags", err.Error())
+			return
+		}
+	}
+
+	// update tenant and owner
+	if err := r.readBucketInfo(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not get bucket info", err.Error())
+		return
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -170,6 +575,14 @@ func (r *BucketResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
+	// empty bucket
+	if data.ForceDestroy.ValueBool() {
+		if err := emptyBucket(ctx, r.client.S3, data.Id.ValueString()); err != nil {
+			resp.Diagnostics.AddError("could not empty bucket", err.Error())
+			return
+		}
+	}
+
 	s3req := &s3.DeleteBucketInput{
 		Bucket: aws.String(data.Id.ValueString()),
 	}
@@ -180,3 +593,439 @@ func (r *BucketResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 }
+
+func (r *BucketResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, err := parseBucketImportId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("invalid import id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("force_destroy"), false)...)
+}
+
+// readBucketInfo updates tenant, owner, placement target and storage class
+// from the bucket info of the admin api.
+func (r *BucketResource) readBucketInfo(ctx context.Context, data *BucketResourceModel) error {
+	info, err := r.client.Admin.GetBucketInfo(ctx, admin.Bucket{
+		Bucket: adminBucketName(data.Id.ValueString()),
+	})
+	if err != nil {
+		return err
+	}
+
+	// go-ceph does not decode the tenant of the bucket info
+	data.Tenant = types.StringValue(bucketTenant(data.Id.ValueString()))
+	data.Owner = types.StringValue(info.Owner)
+
+	// placement rule is "<placement-target>[/<storage-class>]"
+	splittedRule := strings.SplitN(info.PlacementRule, "/", 2)
+	data.PlacementTarget = types.StringValue(splittedRule[0])
+	if len(splittedRule) == 2 {
+		data.StorageClass = types.StringValue(splittedRule[1])
+	} else {
+		data.StorageClass = types.StringValue("STANDARD")
+	}
+
+	return nil
+}
+
+// linkBucket links a bucket to a user via the admin api. If the user belongs to
+// another tenant, the bucket is moved into the tenant of the user. The bucket is
+// unlinked from the previous owner first, so it is removed from the bucket list
+// of the previous owner.
+func linkBucket(ctx context.Context, client *admin.API, bucket string, uid string) error {
+	info, err := client.GetBucketInfo(ctx, admin.Bucket{
+		Bucket: adminBucketName(bucket),
+	})
+	if err != nil {
+		return err
+	}
+
+	previousOwner := info.Owner
+	if previousOwner != "" && previousOwner != uid {
+		tflog.Info(ctx, fmt.Sprintf("unlink bucket %s from user %s", bucket, previousOwner))
+		err := client.UnlinkBucket(ctx, admin.BucketLinkInput{
+			Bucket: adminBucketName(bucket),
+			UID:    previousOwner,
+		})
+		if err != nil {
+			return fmt.Errorf("could not unlink bucket from '%s': %w", previousOwner, err)
+		}
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("link bucket %s to user %s", bucket, uid))
+	err = client.LinkBucket(ctx, admin.BucketLinkInput{
+		Bucket:   adminBucketName(bucket),
+		BucketID: info.ID,
+		UID:      uid,
+	})
+	if err != nil && previousOwner != "" && previousOwner != uid {
+		// do not leave the bucket without owner
+		restoreErr := client.LinkBucket(ctx, admin.BucketLinkInput{
+			Bucket:   adminBucketName(bucket),
+			BucketID: info.ID,
+			UID:      previousOwner,
+		})
+		if restoreErr != nil {
+			return fmt.Errorf("%w, could not link bucket to previous owner '%s' again: %s", err, previousOwner, restoreErr.Error())
+		}
+	}
+	return err
+}
+
+// bucketId returns the bucket name as used in S3 requests, "tenant:bucket" or
+// "bucket" without a tenant.
+func bucketId(tenant string, bucket string) string {
+	if tenant == "" {
+		return bucket
+	}
+	return fmt.Sprintf("%s:%s", tenant, bucket)
+}
+
+// userTenant returns the tenant of a user id ("tenant$user").
+func userTenant(uid string) string {
+	if splittedUid := strings.SplitN(uid, "$", 2); len(splittedUid) == 2 {
+		return splittedUid[0]
+	}
+	return ""
+}
+
+// bucketName returns the bucket name without the tenant of a bucket name as
+// used in S3 requests ("tenant:bucket").
+func bucketName(bucket string) string {
+	if splittedBucket := strings.SplitN(bucket, ":", 2); len(splittedBucket) == 2 {
+		return splittedBucket[1]
+	}
+	return bucket
+}
+
+// bucketTenant returns the tenant of a bucket name as used in S3 requests
+// ("tenant:bucket").
+func bucketTenant(bucket string) string {
+	if splittedBucket := strings.SplitN(bucket, ":", 2); len(splittedBucket) == 2 {
+		return splittedBucket[0]
+	}
+	return ""
+}
+
+// bucketOwnerTenant returns the tenant of the owner of a bucket. Before the
+// bucket is linked to another user, this is the tenant of the bucket.
+func bucketOwnerTenant(ctx context.Context, client *s3.Client, bucket string) (string, error) {
+	acl, err := client.GetBucketAcl(ctx, &s3.GetBucketAclInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return "", err
+	}
+	if acl.Owner == nil {
+		return "", nil
+	}
+	return userTenant(aws.StringValue(acl.Owner.ID)), nil
+}
+
+// adminBucketName converts a bucket name as used in S3 requests ("tenant:bucket")
+// into the form expected by the admin api ("tenant/bucket").
+func adminBucketName(bucket string) string {
+	return strings.Replace(bucket, ":", "/", 1)
+}
+
+func putBucketVersioning(ctx context.Context, client *s3.Client, bucket string, status string) error {
+	_, err := client.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+		Bucket: aws.String(bucket),
+		VersioningConfiguration: &s3types.VersioningConfiguration{
+			Status: s3types.BucketVersioningStatus(status),
+		},
+	})
+	return err
+}
+
+// getBucketVersioning returns the versioning state of a bucket, or null if
+// versioning was never enabled.
+func getBucketVersioning(ctx context.Context, client *s3.Client, bucket string) (types.String, error) {
+	res, err := client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return types.StringNull(), err
+	}
+	if res.Status == "" {
+		return types.StringNull(), nil
+	}
+	return types.StringValue(string(res.Status)), nil
+}
+
+func putObjectLockConfiguration(ctx context.Context, client *s3.Client, bucket string, retention *BucketObjectLockRetentionModel) error {
+	config := &s3types.ObjectLockConfiguration{
+		ObjectLockEnabled: s3types.ObjectLockEnabledEnabled,
+	}
+	if retention != nil {
+		config.Rule = &s3types.ObjectLockRule{
+			DefaultRetention: &s3types.DefaultRetention{
+				Mode:  s3types.ObjectLockRetentionMode(retention.Mode.ValueString()),
+				Days:  int32(retention.Days.ValueInt64()),
+				Years: int32(retention.Years.ValueInt64()),
+			},
+		}
+	}
+
+	_, err := client.PutObjectLockConfiguration(ctx, &s3.PutObjectLockConfigurationInput{
+		Bucket:                  aws.String(bucket),
+		ObjectLockConfiguration: config,
+	})
+	return err
+}
+
+func objectLockRetentionEqual(a, b *BucketObjectLockRetentionModel) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Mode.Equal(b.Mode) && a.Days.Equal(b.Days) && a.Years.Equal(b.Years)
+}
+
+func putBucketTagging(ctx context.Context, client *s3.Client, bucket string, tags map[string]string) error {
+	tagSet := make([]s3types.Tag, 0, len(tags))
+	for k, v := range tags {
+		tagSet = append(tagSet, s3types.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(v),
+		})
+	}
+
+	_, err := client.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+		Bucket: aws.String(bucket),
+		Tagging: &s3types.Tagging{
+			TagSet: tagSet,
+		},
+	})
+	return err
+}
+
+func getBucketTagging(ctx context.Context, client *s3.Client, bucket string) (map[string]string, error) {
+	s3res, err := client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == "NoSuchTagSet" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if len(s3res.TagSet) == 0 {
+		return nil, nil
+	}
+	tags := make(map[string]string, len(s3res.TagSet))
+	for _, t := range s3res.TagSet {
+		tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+	}
+	return tags, nil
+}
+
+func tagsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
+
+// parseBucketImportId accepts "bucket" and "tenant/bucket" and returns the
+// bucket name as used in S3 requests ("bucket" or "tenant:bucket").
+func parseBucketImportId(id string) (string, error) {
+	splittedId := strings.SplitN(id, "/", 2)
+	if len(splittedId) == 1 {
+		if id == "" {
+			return "", fmt.Errorf("expected import id in the form 'bucket' or 'tenant/bucket', got '%s'", id)
+		}
+		return id, nil
+	}
+
+	if splittedId[0] == "" || splittedId[1] == "" {
+		return "", fmt.Errorf("expected import id in the form 'bucket' or 'tenant/bucket', got '%s'", id)
+	}
+
+	return fmt.Sprintf("%s:%s", splittedId[0], splittedId[1]), nil
+}
+
+// emptyBucket aborts all multipart uploads and deletes all object versions and
+// delete markers of a bucket.
+func emptyBucket(ctx context.Context, client *s3.Client, bucket string) error {
+	ctx = tflog.SetField(ctx, "bucket", bucket)
+
+	// abort unfinished multipart uploads
+	aborted, err := abortMultipartUploads(ctx, client, bucket)
+	if err != nil {
+		return err
+	}
+	tflog.Info(ctx, fmt.Sprintf("aborted %d multipart uploads", aborted))
+
+	// delete object versions and delete markers
+	deleted, err := deleteObjectVersions(ctx, client, bucket)
+	if err != nil {
+		return err
+	}
+	tflog.Info(ctx, fmt.Sprintf("deleted %d object versions and delete markers", deleted))
+
+	return nil
+}
+
+// parallelWorker runs jobs with bounded concurrency and remembers the first error.
+type parallelWorker struct {
+	wg  sync.WaitGroup
+	sem chan struct{}
+	mu  sync.Mutex
+	err error
+}
+
+func newParallelWorker(concurrency int) *parallelWorker {
+	return &parallelWorker{
+		sem: make(chan struct{}, concurrency),
+	}
+}
+
+func (w *parallelWorker) Go(job func() error) {
+	w.sem <- struct{}{}
+	w.wg.Add(1)
+	go func() {
+		defer func() {
+			<-w.sem
+			w.wg.Done()
+		}()
+		if err := job(); err != nil {
+			w.mu.Lock()
+			if w.err == nil {
+				w.err = err
+			}
+			w.mu.Unlock()
+		}
+	}()
+}
+
+func (w *parallelWorker) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+func (w *parallelWorker) Wait() error {
+	w.wg.Wait()
+	return w.Err()
+}
+
+func abortMultipartUploads(ctx context.Context, client *s3.Client, bucket string) (int, error) {
+	worker := newParallelWorker(forceDestroyConcurrency)
+	count := 0
+
+	s3req := &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(bucket),
+	}
+	for {
+		s3res, err := client.ListMultipartUploads(ctx, s3req)
+		if err != nil {
+			_ = worker.Wait()
+			return count, fmt.Errorf("could not list multipart uploads: %w", err)
+		}
+
+		for _, u := range s3res.Uploads {
+			upload := u
+			worker.Go(func() error {
+				_, err := client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+					Bucket:   aws.String(bucket),
+					Key:      upload.Key,
+					UploadId: upload.UploadId,
+				})
+				if err != nil {
+					var ae smithy.APIError
+					if errors.As(err, &ae) && ae.ErrorCode() == "NoSuchUpload" {
+						return nil
+					}
+					return fmt.Errorf("could not abort multipart upload '%s' of '%s': %w", aws.StringValue(upload.UploadId), aws.StringValue(upload.Key), err)
+				}
+				return nil
+			})
+		}
+		count += len(s3res.Uploads)
+
+		if worker.Err() != nil || !s3res.IsTruncated {
+			break
+		}
+		s3req.KeyMarker = s3res.NextKeyMarker
+		s3req.UploadIdMarker = s3res.NextUploadIdMarker
+	}
+
+	return count, worker.Wait()
+}
+
+func deleteObjectVersions(ctx context.Context, client *s3.Client, bucket string) (int, error) {
+	worker := newParallelWorker(forceDestroyConcurrency)
+	count := 0
+
+	deleteBatch := func(objects []s3types.ObjectIdentifier) {
+		worker.Go(func() error {
+			s3res, err := client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+				Bucket: aws.String(bucket),
+				Delete: &s3types.Delete{
+					Objects: objects,
+					Quiet:   true,
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("could not delete objects: %w", err)
+			}
+			if len(s3res.Errors) > 0 {
+				e := s3res.Errors[0]
+				return fmt.Errorf("could not delete %d objects, first error for '%s': %s", len(s3res.Errors), aws.StringValue(e.Key), aws.StringValue(e.Message))
+			}
+			tflog.Debug(ctx, fmt.Sprintf("deleted batch of %d objects", len(objects)))
+			return nil
+		})
+	}
+
+	s3req := &s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucket),
+	}
+	batch := make([]s3types.ObjectIdentifier, 0, deleteObjectsBatchSize)
+	for {
+		s3res, err := client.ListObjectVersions(ctx, s3req)
+		if err != nil {
+			_ = worker.Wait()
+			return count, fmt.Errorf("could not list object versions: %w", err)
+		}
+
+		objects := make([]s3types.ObjectIdentifier, 0, len(s3res.Versions)+len(s3res.DeleteMarkers))
+		for _, v := range s3res.Versions {
+			objects = append(objects, s3types.ObjectIdentifier{Key: v.Key, VersionId: v.VersionId})
+		}
+		for _, m := range s3res.DeleteMarkers {
+			objects = append(objects, s3types.ObjectIdentifier{Key: m.Key, VersionId: m.VersionId})
+		}
+
+		for _, o := range objects {
+			batch = append(batch, o)
+			if len(batch) == deleteObjectsBatchSize {
+				deleteBatch(batch)
+				batch = make([]s3types.ObjectIdentifier, 0, deleteObjectsBatchSize)
+			}
+		}
+		count += len(objects)
+
+		if worker.Err() != nil || !s3res.IsTruncated {
+			break
+		}
+		tflog.Debug(ctx, fmt.Sprintf("queued %d object versions for deletion", count))
+		s3req.KeyMarker = s3res.NextKeyMarker
+		s3req.VersionIdMarker = s3res.NextVersionIdMarker
+	}
+	if len(batch) > 0 && worker.Err() == nil {
+		deleteBatch(batch)
+	}
+
+	return count, worker.Wait()
+}
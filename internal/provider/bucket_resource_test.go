@@ -0,0 +1,376 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccBucketResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketResourceConfig("Enabled", "infra"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "name", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "versioning", "Enabled"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "object_lock_enabled", "false"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "tags.team", "infra"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "placement_target", "default-placement"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "storage_class", "STANDARD"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "rgw_bucket.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"force_destroy"},
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketResourceConfig("Suspended", "storage"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket.test", "versioning", "Suspended"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "tags.team", "storage"),
+					testAccCheckBucketSubresource(fake, "test", "tagging", "storage"),
+				),
+			},
+			// Drift testing, removed tags are restored
+			{
+				PreConfig: func() {
+					fake.modifyFakeBucket("test", func(bucket *fakeBucket) {
+						delete(bucket.subresources, "tagging")
+					})
+				},
+				Config: fake.providerConfig() + testAccBucketResourceConfig("Suspended", "storage"),
+				Check:  testAccCheckBucketSubresource(fake, "test", "tagging", "storage"),
+			},
+			// Drift testing, buckets deleted outside of terraform are recreated
+			{
+				PreConfig: func() {
+					fake.deleteBucket("test")
+				},
+				Config: fake.providerConfig() + testAccBucketResourceConfig("Suspended", "storage"),
+				Check:  testAccCheckBucketSubresource(fake, "test", "tagging", "storage"),
+			},
+			// Update testing, empty tags remove the tags and are kept
+			{
+				Config: fake.providerConfig() + `
+resource "rgw_bucket" "test" {
+  name = "test"
+  tags = {}
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket.test", "tags.%", "0"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "versioning", "Suspended"),
+					testAccCheckBucketSubresourceDeleted(fake, "test", "tagging"),
+				),
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccBucketResourceConfig(versioning string, team string) string {
+	return fmt.Sprintf(`
+resource "rgw_bucket" "test" {
+  name       = "test"
+  versioning = %[1]q
+
+  tags = {
+    team = %[2]q
+  }
+}
+`, versioning, team)
+}
+
+func TestAccBucketResource_owner(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testAccCheckBucketDestroyed(fake, "tenant:test"),
+			testAccCheckBucketDestroyed(fake, "other:test"),
+		),
+		Steps: []resource.TestStep{
+			// Create and Read testing, the bucket is linked to the owner in another tenant
+			{
+				Config: fake.providerConfig() + testAccBucketResourceOwnerConfig("owner"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket.test", "id", "tenant:test"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "tenant", "tenant"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "owner", "tenant$owner"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "tags.team", "infra"),
+					testAccCheckBucketOwner(fake, "tenant:test", "tenant$owner"),
+					testAccCheckBucketSubresource(fake, "tenant:test", "tagging", "infra"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "rgw_bucket.test",
+				ImportState:             true,
+				ImportStateId:           "tenant/test",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"force_destroy"},
+			},
+			// Update testing, a new owner in another tenant moves the bucket
+			{
+				Config: fake.providerConfig() + testAccBucketResourceOwnerConfig("other"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket.test", "id", "other:test"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "tenant", "other"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "owner", "other$other"),
+					testAccCheckBucketOwner(fake, "other:test", "other$other"),
+					testAccCheckBucketDestroyed(fake, "tenant:test"),
+				),
+			},
+			// Drift testing, the bucket is linked again to the owner
+			{
+				PreConfig: func() {
+					fake.modifyFakeBucket("other:test", func(bucket *fakeBucket) {
+						bucket.info.Owner = "other$intruder"
+					})
+				},
+				Config: fake.providerConfig() + testAccBucketResourceOwnerConfig("other"),
+				Check:  testAccCheckBucketOwner(fake, "other:test", "other$other"),
+			},
+		},
+	})
+}
+
+func TestAccBucketResource_tenant(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "tenant:test"),
+		Steps: []resource.TestStep{
+			// the last config is used to destroy, so the invalid one comes first
+			{
+				Config: fake.providerConfig() + `
+resource "rgw_bucket" "test" {
+  name   = "test"
+  tenant = "other"
+  owner  = "tenant$owner"
+}
+`,
+				ExpectError: regexp.MustCompile(`the owner must belong to the tenant of the bucket`),
+			},
+			{
+				Config: fake.providerConfig() + `
+resource "rgw_bucket" "test" {
+  name   = "test"
+  tenant = "tenant"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket.test", "id", "tenant:test"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "tenant", "tenant"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "owner", fakeRgwS3User),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBucketResource_s3CredentialsTenant(t *testing.T) {
+	fake := newFakeRgw(t)
+	fake.useS3User("s3tenant$s3user")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testAccCheckBucketDestroyed(fake, "s3tenant:test"),
+			testAccCheckBucketDestroyed(fake, "other:owned"),
+		),
+		Steps: []resource.TestStep{
+			// buckets are created in the tenant of the S3 credentials, a bucket
+			// with owner is linked from there into the tenant of the owner
+			{
+				Config: fake.providerConfig() + testAccNamedUserConfig("owner", "other", "Owner") + testAccBucketConfig + `
+resource "rgw_bucket" "owned" {
+  name  = "owned"
+  owner = rgw_user.owner.id
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket.test", "id", "s3tenant:test"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "tenant", "s3tenant"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "owner", "s3tenant$s3user"),
+					resource.TestCheckResourceAttr("rgw_bucket.owned", "id", "other:owned"),
+					resource.TestCheckResourceAttr("rgw_bucket.owned", "tenant", "other"),
+					resource.TestCheckResourceAttr("rgw_bucket.owned", "owner", "other$owner"),
+					testAccCheckBucketOwner(fake, "other:owned", "other$owner"),
+					testAccCheckBucketDestroyed(fake, "s3tenant:owned"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBucketResource_forceDestroy(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + testAccBucketResourceForceDestroyConfig("Enabled", true),
+			},
+			// objects with several versions, delete markers and multipart uploads,
+			// more than a single page of each, are removed on destroy
+			{
+				PreConfig: func() {
+					for i := 0; i < 4; i++ {
+						key := fmt.Sprintf("object-%d", i)
+						fake.putFakeObject("test", key, "first")
+						fake.putFakeObject("test", key, "second")
+						fake.startFakeUpload("test", key)
+					}
+					fake.deleteFakeObject("test", "object-0")
+				},
+				Config: fake.providerConfig() + testAccBucketResourceForceDestroyConfig("Enabled", true),
+				Check:  testAccCheckBucketVersionCount(fake, "test", 9),
+			},
+		},
+	})
+}
+
+func TestAccBucketResource_forceDestroyDisabled(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + testAccBucketResourceForceDestroyConfig("Suspended", false),
+			},
+			// non-empty buckets are not deleted without force_destroy
+			{
+				PreConfig: func() {
+					fake.putFakeObject("test", "object", "body")
+				},
+				Config:      fake.providerConfig(),
+				ExpectError: regexp.MustCompile(`BucketNotEmpty`),
+			},
+			{
+				PreConfig: func() {
+					fake.deleteFakeObject("test", "object")
+				},
+				Config: fake.providerConfig(),
+				Check:  testAccCheckBucketDestroyed(fake, "test"),
+			},
+		},
+	})
+}
+
+func testAccBucketResourceForceDestroyConfig(versioning string, forceDestroy bool) string {
+	return fmt.Sprintf(`
+resource "rgw_bucket" "test" {
+  name          = "test"
+  versioning    = %[1]q
+  force_destroy = %[2]t
+}
+`, versioning, forceDestroy)
+}
+
+func testAccBucketResourceOwnerConfig(owner string) string {
+	return testAccNamedUserConfig("owner", "tenant", "Owner") + testAccNamedUserConfig("other", "other", "Other") + fmt.Sprintf(`
+resource "rgw_bucket" "test" {
+  name  = "test"
+  owner = rgw_user.%[1]s.id
+
+  tags = {
+    team = "infra"
+  }
+}
+`, owner)
+}
+
+// testAccCheckBucketOwner checks the owner of a bucket in the fake.
+func testAccCheckBucketOwner(fake *fakeRgw, bucket string, owner string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		b := fake.bucket(bucket)
+		if b == nil {
+			return fmt.Errorf("bucket '%s' does not exist", bucket)
+		}
+		if b.info.Owner != owner {
+			return fmt.Errorf("expected owner '%s' of bucket '%s', got '%s'", owner, bucket, b.info.Owner)
+		}
+		return nil
+	}
+}
+
+// testAccCheckBucketVersionCount checks the number of object versions and
+// delete markers of a bucket in the fake.
+func testAccCheckBucketVersionCount(fake *fakeRgw, bucket string, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		b := fake.bucket(bucket)
+		if b == nil {
+			return fmt.Errorf("bucket '%s' does not exist", bucket)
+		}
+		if n := len(b.objects) + len(b.versions); n != count {
+			return fmt.Errorf("expected %d object versions in bucket '%s', got %d", count, bucket, n)
+		}
+		return nil
+	}
+}
+
+// testAccCheckBucketDestroyed checks that a bucket does not exist in the fake.
+func testAccCheckBucketDestroyed(fake *fakeRgw, bucket string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if fake.bucket(bucket) != nil {
+			return fmt.Errorf("bucket '%s' still exists", bucket)
+		}
+		return nil
+	}
+}
+
+// testAccCheckBucketSubresource checks that a bucket subresource (e.g. "policy")
+// is configured in the fake and contains the expected string.
+func testAccCheckBucketSubresource(fake *fakeRgw, bucket string, subresource string, contains string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		b := fake.bucket(bucket)
+		if b == nil {
+			return fmt.Errorf("bucket '%s' does not exist", bucket)
+		}
+		body, ok := b.subresources[subresource]
+		if !ok {
+			return fmt.Errorf("bucket '%s' has no %s configuration", bucket, subresource)
+		}
+		if !strings.Contains(string(body), contains) {
+			return fmt.Errorf("expected %s configuration of bucket '%s' to contain '%s', got '%s'", subresource, bucket, contains, body)
+		}
+		return nil
+	}
+}
+
+// testAccCheckBucketSubresourceDeleted checks that a bucket subresource is not
+// configured in the fake.
+func testAccCheckBucketSubresourceDeleted(fake *fakeRgw, bucket string, subresource string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		b := fake.bucket(bucket)
+		if b == nil {
+			return nil
+		}
+		if _, ok := b.subresources[subresource]; ok {
+			return fmt.Errorf("bucket '%s' still has a %s configuration", bucket, subresource)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,275 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithConfigure = &BucketServerSideEncryptionConfigurationResource{}
+var _ resource.ResourceWithImportState = &BucketServerSideEncryptionConfigurationResource{}
+var _ resource.ResourceWithValidateConfig = &BucketServerSideEncryptionConfigurationResource{}
+
+func NewBucketServerSideEncryptionConfigurationResource() resource.Resource {
+	return &BucketServerSideEncryptionConfigurationResource{}
+}
+
+type BucketServerSideEncryptionConfigurationResource struct {
+	client *RgwClient
+}
+
+type BucketServerSideEncryptionConfigurationResourceModel struct {
+	Id             types.String `tfsdk:"id"`
+	Bucket         types.String `tfsdk:"bucket"`
+	SSEAlgorithm   types.String `tfsdk:"sse_algorithm"`
+	KMSMasterKeyId types.String `tfsdk:"kms_master_key_id"`
+}
+
+func (r *BucketServerSideEncryptionConfigurationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_server_side_encryption_configuration"
+}
+
+func (r *BucketServerSideEncryptionConfigurationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Default server side encryption of new objects in a bucket in Ceph RGW. Requires a KMS backend configured in RGW, `rgw_crypt_sse_s3_backend` for `AES256` and `rgw_crypt_s3_kms_backend` for `aws:kms`.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bucket": schema.StringAttribute{
+				MarkdownDescription: "Bucket Name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"sse_algorithm": schema.StringAttribute{
+				MarkdownDescription: "Server side encryption algorithm. Valid values are `AES256` (SSE-S3) and `aws:kms` (SSE-KMS).",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(s3types.ServerSideEncryptionAes256), string(s3types.ServerSideEncryptionAwsKms)),
+				},
+			},
+			"kms_master_key_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the key in the KMS backend. Required if `sse_algorithm` is `aws:kms`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func (r *BucketServerSideEncryptionConfigurationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *BucketServerSideEncryptionConfigurationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *BucketServerSideEncryptionConfigurationResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.SSEAlgorithm.IsUnknown() || data.KMSMasterKeyId.IsUnknown() {
+		return
+	}
+
+	// RGW has no default key for SSE-KMS
+	kms := data.SSEAlgorithm.ValueString() == string(s3types.ServerSideEncryptionAwsKms)
+	if kms && data.KMSMasterKeyId.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("kms_master_key_id"), "missing kms key", "kms_master_key_id is required if sse_algorithm is 'aws:kms'")
+	}
+	if !kms && !data.KMSMasterKeyId.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("kms_master_key_id"), "unexpected kms key", "kms_master_key_id can only be set if sse_algorithm is 'aws:kms'")
+	}
+}
+
+func (r *BucketServerSideEncryptionConfigurationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketServerSideEncryptionConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketEncryption
+	if err := r.put(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not create bucket server side encryption configuration", encryptionErrorDetail(err))
+		return
+	}
+
+	// use bucket name as resource id
+	data.Id = types.StringValue(data.Bucket.ValueString())
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketServerSideEncryptionConfigurationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketServerSideEncryptionConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create GetBucketEncryption Request
+	s3req := &s3.GetBucketEncryptionInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	}
+
+	s3res, err := r.client.S3.GetBucketEncryption(ctx, s3req)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) {
+			switch ae.ErrorCode() {
+			case "NoSuchBucket", "ServerSideEncryptionConfigurationNotFoundError":
+				resp.State.RemoveResource(ctx)
+				return
+			}
+		}
+		resp.Diagnostics.AddError("could not get bucket server side encryption configuration", err.Error())
+		return
+	}
+
+	if s3res.ServerSideEncryptionConfiguration == nil || len(s3res.ServerSideEncryptionConfiguration.Rules) == 0 ||
+		s3res.ServerSideEncryptionConfiguration.Rules[0].ApplyServerSideEncryptionByDefault == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	encryption := s3res.ServerSideEncryptionConfiguration.Rules[0].ApplyServerSideEncryptionByDefault
+	data.SSEAlgorithm = types.StringValue(string(encryption.SSEAlgorithm))
+	data.KMSMasterKeyId = types.StringNull()
+	if aws.StringValue(encryption.KMSMasterKeyID) != "" {
+		data.KMSMasterKeyId = types.StringValue(*encryption.KMSMasterKeyID)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketServerSideEncryptionConfigurationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketServerSideEncryptionConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketEncryption
+	if err := r.put(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not modify bucket server side encryption configuration", encryptionErrorDetail(err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketServerSideEncryptionConfigurationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketServerSideEncryptionConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	s3req := &s3.DeleteBucketEncryptionInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	}
+
+	_, err := r.client.S3.DeleteBucketEncryption(ctx, s3req)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == "NoSuchBucket" {
+			return
+		}
+		resp.Diagnostics.AddError("could not delete bucket server side encryption configuration", err.Error())
+		return
+	}
+}
+
+func (r *BucketServerSideEncryptionConfigurationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	bucket, err := parseBucketImportId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("invalid import id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), bucket)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), bucket)...)
+}
+
+func (r *BucketServerSideEncryptionConfigurationResource) put(ctx context.Context, data *BucketServerSideEncryptionConfigurationResourceModel) error {
+	encryption := &s3types.ServerSideEncryptionByDefault{
+		SSEAlgorithm: s3types.ServerSideEncryption(data.SSEAlgorithm.ValueString()),
+	}
+	if !data.KMSMasterKeyId.IsNull() {
+		encryption.KMSMasterKeyID = aws.String(data.KMSMasterKeyId.ValueString())
+	}
+
+	_, err := r.client.S3.PutBucketEncryption(ctx, &s3.PutBucketEncryptionInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+		ServerSideEncryptionConfiguration: &s3types.ServerSideEncryptionConfiguration{
+			Rules: []s3types.ServerSideEncryptionRule{
+				{ApplyServerSideEncryptionByDefault: encryption},
+			},
+		},
+	})
+	return err
+}
+
+// encryptionErrorDetail explains errors returned by RGW if server side
+// encryption is not available, e.g. because no KMS backend is configured.
+func encryptionErrorDetail(err error) string {
+	var ae smithy.APIError
+	if !errors.As(err, &ae) {
+		return err.Error()
+	}
+
+	switch code := ae.ErrorCode(); {
+	case code == "NotImplemented", code == "InvalidArgument", code == "InvalidRequest", strings.HasPrefix(code, "KMS"):
+		return fmt.Sprintf("RGW rejected the encryption configuration, probably server side encryption is not available in the cluster. "+
+			"SSE-S3 (AES256) requires 'rgw_crypt_sse_s3_backend' and SSE-KMS (aws:kms) requires 'rgw_crypt_s3_kms_backend' to be configured in RGW, "+
+			"and the key must exist in the KMS backend: %s", err.Error())
+	}
+	return err.Error()
+}
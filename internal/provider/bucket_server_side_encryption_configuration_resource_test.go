@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBucketServerSideEncryptionConfigurationResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketServerSideEncryptionConfigurationResourceConfig(`sse_algorithm = "AES256"`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_server_side_encryption_configuration.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket_server_side_encryption_configuration.test", "sse_algorithm", "AES256"),
+					resource.TestCheckNoResourceAttr("rgw_bucket_server_side_encryption_configuration.test", "kms_master_key_id"),
+					testAccCheckBucketSubresource(fake, "test", "encryption", "AES256"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_bucket_server_side_encryption_configuration.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketServerSideEncryptionConfigurationResourceConfig(`
+  sse_algorithm     = "aws:kms"
+  kms_master_key_id = "test-key"
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_server_side_encryption_configuration.test", "sse_algorithm", "aws:kms"),
+					resource.TestCheckResourceAttr("rgw_bucket_server_side_encryption_configuration.test", "kms_master_key_id", "test-key"),
+					testAccCheckBucketSubresource(fake, "test", "encryption", "test-key"),
+				),
+			},
+			// Drift testing, deleted configurations are restored
+			{
+				PreConfig: func() {
+					fake.modifyFakeBucket("test", func(bucket *fakeBucket) {
+						delete(bucket.subresources, "encryption")
+					})
+				},
+				Config: fake.providerConfig() + testAccBucketServerSideEncryptionConfigurationResourceConfig(`
+  sse_algorithm     = "aws:kms"
+  kms_master_key_id = "test-key"
+`),
+				Check: testAccCheckBucketSubresource(fake, "test", "encryption", "test-key"),
+			},
+			// Delete testing, the bucket is kept
+			{
+				Config: fake.providerConfig() + testAccBucketConfig,
+				Check:  testAccCheckBucketSubresourceDeleted(fake, "test", "encryption"),
+			},
+		},
+	})
+}
+
+func TestAccBucketServerSideEncryptionConfigurationResource_invalid(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fake.providerConfig() + testAccBucketServerSideEncryptionConfigurationResourceConfig(`sse_algorithm = "aws:kms"`),
+				ExpectError: regexp.MustCompile(`missing kms key`),
+			},
+			{
+				Config: fake.providerConfig() + testAccBucketServerSideEncryptionConfigurationResourceConfig(`
+  sse_algorithm     = "AES256"
+  kms_master_key_id = "test-key"
+`),
+				ExpectError: regexp.MustCompile(`unexpected kms key`),
+			},
+		},
+	})
+}
+
+func TestAccBucketServerSideEncryptionConfigurationResource_noKMS(t *testing.T) {
+	// error codes of RGW without sse-s3 backend, without kms backend and with
+	// a key missing in the kms backend
+	for code, encryption := range map[string]string{
+		"NotImplemented": `sse_algorithm = "AES256"`,
+		"InvalidArgument": `
+  sse_algorithm     = "aws:kms"
+  kms_master_key_id = "test-key"
+`,
+		"KMS.NotFound": `
+  sse_algorithm     = "aws:kms"
+  kms_master_key_id = "missing"
+`,
+	} {
+		t.Run(code, func(t *testing.T) {
+			fake := newFakeRgw(t)
+			fake.rejectSubresource("encryption", code)
+
+			resource.Test(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+				Steps: []resource.TestStep{
+					{
+						Config:      fake.providerConfig() + testAccBucketServerSideEncryptionConfigurationResourceConfig(encryption),
+						ExpectError: regexp.MustCompile(`encryption\s+is\s+not\s+available`),
+					},
+				},
+			})
+		})
+	}
+}
+
+func testAccBucketServerSideEncryptionConfigurationResourceConfig(encryption string) string {
+	return testAccBucketConfig + fmt.Sprintf(`
+resource "rgw_bucket_server_side_encryption_configuration" "test" {
+  bucket = rgw_bucket.test.name
+  %s
+}
+`, encryption)
+}
@@ -0,0 +1,453 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithConfigure = &BucketWebsiteConfigurationResource{}
+var _ resource.ResourceWithImportState = &BucketWebsiteConfigurationResource{}
+var _ resource.ResourceWithValidateConfig = &BucketWebsiteConfigurationResource{}
+
+func NewBucketWebsiteConfigurationResource() resource.Resource {
+	return &BucketWebsiteConfigurationResource{}
+}
+
+type BucketWebsiteConfigurationResource struct {
+	client *RgwClient
+}
+
+type BucketWebsiteConfigurationResourceModel struct {
+	Id                    types.String                    `tfsdk:"id"`
+	Bucket                types.String                    `tfsdk:"bucket"`
+	IndexDocumentSuffix   types.String                    `tfsdk:"index_document_suffix"`
+	ErrorDocumentKey      types.String                    `tfsdk:"error_document_key"`
+	RedirectAllRequestsTo *BucketWebsiteRedirectAllModel  `tfsdk:"redirect_all_requests_to"`
+	RoutingRules          []BucketWebsiteRoutingRuleModel `tfsdk:"routing_rule"`
+	WebsiteEndpoint       types.String                    `tfsdk:"website_endpoint"`
+}
+
+type BucketWebsiteRedirectAllModel struct {
+	HostName types.String `tfsdk:"host_name"`
+	Protocol types.String `tfsdk:"protocol"`
+}
+
+type BucketWebsiteRoutingRuleModel struct {
+	Condition *BucketWebsiteConditionModel `tfsdk:"condition"`
+	Redirect  *BucketWebsiteRedirectModel  `tfsdk:"redirect"`
+}
+
+type BucketWebsiteConditionModel struct {
+	HttpErrorCodeReturnedEquals types.String `tfsdk:"http_error_code_returned_equals"`
+	KeyPrefixEquals             types.String `tfsdk:"key_prefix_equals"`
+}
+
+type BucketWebsiteRedirectModel struct {
+	HostName             types.String `tfsdk:"host_name"`
+	HttpRedirectCode     types.String `tfsdk:"http_redirect_code"`
+	Protocol             types.String `tfsdk:"protocol"`
+	ReplaceKeyPrefixWith types.String `tfsdk:"replace_key_prefix_with"`
+	ReplaceKeyWith       types.String `tfsdk:"replace_key_with"`
+}
+
+func (r *BucketWebsiteConfigurationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bucket_website_configuration"
+}
+
+func (r *BucketWebsiteConfigurationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	protocolValidator := stringvalidator.OneOf(string(s3types.ProtocolHttp), string(s3types.ProtocolHttps))
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Bucket Website Configuration in Ceph RGW, served by the s3website frontend. Either `index_document_suffix` or `redirect_all_requests_to` must be set.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bucket": schema.StringAttribute{
+				MarkdownDescription: "Bucket Name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"index_document_suffix": schema.StringAttribute{
+				MarkdownDescription: "Suffix appended to requests for a directory, e.g. `index.html`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[^/]+$`), "must not contain a slash"),
+				},
+			},
+			"error_document_key": schema.StringAttribute{
+				MarkdownDescription: "Object returned if an error occurs, e.g. `404.html`. Requires `index_document_suffix`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+					stringvalidator.AlsoRequires(path.MatchRoot("index_document_suffix")),
+				},
+			},
+			"website_endpoint": schema.StringAttribute{
+				MarkdownDescription: "Host name of the website, `<bucket>.<website_domain>`. Only set if `website_domain` is configured in the provider.",
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"redirect_all_requests_to": schema.SingleNestedBlock{
+				MarkdownDescription: "Redirect all requests to another host.",
+				Attributes: map[string]schema.Attribute{
+					"host_name": schema.StringAttribute{
+						MarkdownDescription: "Host name requests are redirected to",
+						// required if the block is set, checked in ValidateConfig
+						Optional: true,
+					},
+					"protocol": schema.StringAttribute{
+						MarkdownDescription: "Protocol of the redirect. Valid values are `http` and `https`. Defaults to the protocol of the request.",
+						Optional:            true,
+						Validators: []validator.String{
+							protocolValidator,
+						},
+					},
+				},
+			},
+			"routing_rule": schema.ListNestedBlock{
+				MarkdownDescription: "Rules redirecting requests matching a condition. The first matching rule is applied.",
+				Validators: []validator.List{
+					listvalidator.SizeBetween(1, 50),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Blocks: map[string]schema.Block{
+						"condition": schema.SingleNestedBlock{
+							MarkdownDescription: "Condition of the rule. Without condition all requests are redirected.",
+							Attributes: map[string]schema.Attribute{
+								"http_error_code_returned_equals": schema.StringAttribute{
+									MarkdownDescription: "Apply the rule if the request fails with the HTTP status code, e.g. `404`",
+									Optional:            true,
+									Validators: []validator.String{
+										stringvalidator.RegexMatches(regexp.MustCompile(`^[45][0-9]{2}$`), "must be a HTTP error status code"),
+									},
+								},
+								"key_prefix_equals": schema.StringAttribute{
+									MarkdownDescription: "Apply the rule to object keys starting with the prefix, e.g. `docs/`",
+									Optional:            true,
+								},
+							},
+						},
+						"redirect": schema.SingleNestedBlock{
+							MarkdownDescription: "Redirect of requests matching the condition. Required.",
+							Attributes: map[string]schema.Attribute{
+								"host_name": schema.StringAttribute{
+									MarkdownDescription: "Host name of the redirect. Defaults to the host of the request.",
+									Optional:            true,
+								},
+								"http_redirect_code": schema.StringAttribute{
+									MarkdownDescription: "HTTP status code of the redirect, e.g. `301`. Defaults to `301`.",
+									Optional:            true,
+									Validators: []validator.String{
+										stringvalidator.RegexMatches(regexp.MustCompile(`^3[0-9]{2}$`), "must be a HTTP redirect status code"),
+									},
+								},
+								"protocol": schema.StringAttribute{
+									MarkdownDescription: "Protocol of the redirect. Valid values are `http` and `https`. Defaults to the protocol of the request.",
+									Optional:            true,
+									Validators: []validator.String{
+										protocolValidator,
+									},
+								},
+								"replace_key_prefix_with": schema.StringAttribute{
+									MarkdownDescription: "Replace `key_prefix_equals` of the condition in the object key with this prefix. Conflicts with `replace_key_with`.",
+									Optional:            true,
+									Validators: []validator.String{
+										stringvalidator.ConflictsWith(path.MatchRelative().AtParent().AtName("replace_key_with")),
+									},
+								},
+								"replace_key_with": schema.StringAttribute{
+									MarkdownDescription: "Redirect to this object key. Conflicts with `replace_key_prefix_with`.",
+									Optional:            true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *BucketWebsiteConfigurationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *BucketWebsiteConfigurationResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data *BucketWebsiteConfigurationResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.RedirectAllRequestsTo != nil {
+		if data.RedirectAllRequestsTo.HostName.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("redirect_all_requests_to").AtName("host_name"), "missing host name", "host_name is required to redirect all requests")
+		}
+		if !data.IndexDocumentSuffix.IsNull() || len(data.RoutingRules) > 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("redirect_all_requests_to"), "conflicting website configuration", "redirect_all_requests_to can not be combined with index_document_suffix, error_document_key and routing_rule")
+		}
+	} else if data.IndexDocumentSuffix.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("index_document_suffix"), "missing index document", "either index_document_suffix or redirect_all_requests_to must be set")
+	}
+
+	for i, rule := range data.RoutingRules {
+		if rule.Redirect == nil {
+			resp.Diagnostics.AddAttributeError(path.Root("routing_rule").AtListIndex(i).AtName("redirect"), "missing redirect", "each routing_rule requires a redirect block")
+		}
+	}
+}
+
+func (r *BucketWebsiteConfigurationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketWebsiteConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketWebsite
+	if err := r.put(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not create bucket website configuration", err.Error())
+		return
+	}
+
+	// use bucket name as resource id
+	data.Id = types.StringValue(data.Bucket.ValueString())
+	data.WebsiteEndpoint = websiteEndpoint(r.client.WebsiteDomain, data.Bucket.ValueString())
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketWebsiteConfigurationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketWebsiteConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create GetBucketWebsite Request
+	s3req := &s3.GetBucketWebsiteInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	}
+
+	s3res, err := r.client.S3.GetBucketWebsite(ctx, s3req)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) {
+			switch ae.ErrorCode() {
+			case "NoSuchBucket", "NoSuchWebsiteConfiguration":
+				resp.State.RemoveResource(ctx)
+				return
+			}
+		}
+		resp.Diagnostics.AddError("could not get bucket website configuration", err.Error())
+		return
+	}
+
+	data.IndexDocumentSuffix = types.StringNull()
+	if s3res.IndexDocument != nil {
+		data.IndexDocumentSuffix = stringOrNull(s3res.IndexDocument.Suffix)
+	}
+	data.ErrorDocumentKey = types.StringNull()
+	if s3res.ErrorDocument != nil {
+		data.ErrorDocumentKey = stringOrNull(s3res.ErrorDocument.Key)
+	}
+
+	data.RedirectAllRequestsTo = nil
+	if s3res.RedirectAllRequestsTo != nil {
+		data.RedirectAllRequestsTo = &BucketWebsiteRedirectAllModel{
+			HostName: stringOrNull(s3res.RedirectAllRequestsTo.HostName),
+			Protocol: stringOrNull(aws.String(string(s3res.RedirectAllRequestsTo.Protocol))),
+		}
+	}
+
+	data.RoutingRules = make([]BucketWebsiteRoutingRuleModel, len(s3res.RoutingRules))
+	for i, rule := range s3res.RoutingRules {
+		if rule.Condition != nil {
+			data.RoutingRules[i].Condition = &BucketWebsiteConditionModel{
+				HttpErrorCodeReturnedEquals: stringOrNull(rule.Condition.HttpErrorCodeReturnedEquals),
+				KeyPrefixEquals:             stringOrNull(rule.Condition.KeyPrefixEquals),
+			}
+		}
+		if rule.Redirect != nil {
+			data.RoutingRules[i].Redirect = &BucketWebsiteRedirectModel{
+				HostName:             stringOrNull(rule.Redirect.HostName),
+				HttpRedirectCode:     stringOrNull(rule.Redirect.HttpRedirectCode),
+				Protocol:             stringOrNull(aws.String(string(rule.Redirect.Protocol))),
+				ReplaceKeyPrefixWith: stringOrNull(rule.Redirect.ReplaceKeyPrefixWith),
+				ReplaceKeyWith:       stringOrNull(rule.Redirect.ReplaceKeyWith),
+			}
+		}
+	}
+
+	data.WebsiteEndpoint = websiteEndpoint(r.client.WebsiteDomain, data.Bucket.ValueString())
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketWebsiteConfigurationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read Terraform plan data into the model
+	var data *BucketWebsiteConfigurationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutBucketWebsite
+	if err := r.put(ctx, data); err != nil {
+		resp.Diagnostics.AddError("could not modify bucket website configuration", err.Error())
+		return
+	}
+
+	data.WebsiteEndpoint = websiteEndpoint(r.client.WebsiteDomain, data.Bucket.ValueString())
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *BucketWebsiteConfigurationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data *BucketWebsiteConfigurationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	s3req := &s3.DeleteBucketWebsiteInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+	}
+
+	_, err := r.client.S3.DeleteBucketWebsite(ctx, s3req)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == "NoSuchBucket" {
+			return
+		}
+		resp.Diagnostics.AddError("could not delete bucket website configuration", err.Error())
+		return
+	}
+}
+
+func (r *BucketWebsiteConfigurationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	bucket, err := parseBucketImportId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("invalid import id", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), bucket)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), bucket)...)
+}
+
+func (r *BucketWebsiteConfigurationResource) put(ctx context.Context, data *BucketWebsiteConfigurationResourceModel) error {
+	config := &s3types.WebsiteConfiguration{}
+	if !data.IndexDocumentSuffix.IsNull() {
+		config.IndexDocument = &s3types.IndexDocument{Suffix: aws.String(data.IndexDocumentSuffix.ValueString())}
+	}
+	if !data.ErrorDocumentKey.IsNull() {
+		config.ErrorDocument = &s3types.ErrorDocument{Key: aws.String(data.ErrorDocumentKey.ValueString())}
+	}
+	if data.RedirectAllRequestsTo != nil {
+		config.RedirectAllRequestsTo = &s3types.RedirectAllRequestsTo{
+			HostName: aws.String(data.RedirectAllRequestsTo.HostName.ValueString()),
+			Protocol: s3types.Protocol(data.RedirectAllRequestsTo.Protocol.ValueString()),
+		}
+	}
+
+	config.RoutingRules = make([]s3types.RoutingRule, len(data.RoutingRules))
+	for i, rule := range data.RoutingRules {
+		if rule.Condition != nil {
+			config.RoutingRules[i].Condition = &s3types.Condition{
+				HttpErrorCodeReturnedEquals: stringPointer(rule.Condition.HttpErrorCodeReturnedEquals),
+				KeyPrefixEquals:             stringPointer(rule.Condition.KeyPrefixEquals),
+			}
+		}
+		// the redirect is required, but ValidateConfig can not check it if
+		// the config is not known yet
+		if rule.Redirect == nil {
+			return fmt.Errorf("routing rule %d has no redirect", i)
+		}
+		config.RoutingRules[i].Redirect = &s3types.Redirect{
+			HostName:             stringPointer(rule.Redirect.HostName),
+			HttpRedirectCode:     stringPointer(rule.Redirect.HttpRedirectCode),
+			Protocol:             s3types.Protocol(rule.Redirect.Protocol.ValueString()),
+			ReplaceKeyPrefixWith: stringPointer(rule.Redirect.ReplaceKeyPrefixWith),
+			ReplaceKeyWith:       stringPointer(rule.Redirect.ReplaceKeyWith),
+		}
+	}
+
+	_, err := r.client.S3.PutBucketWebsite(ctx, &s3.PutBucketWebsiteInput{
+		Bucket:               aws.String(data.Bucket.ValueString()),
+		WebsiteConfiguration: config,
+	})
+	return err
+}
+
+// websiteEndpoint returns the host name of the website of a bucket, or null
+// if the website domain is not configured. The tenant is not part of the host
+// name, RGW looks up the bucket in the tenant of the request.
+func websiteEndpoint(domain string, bucket string) types.String {
+	if domain == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(fmt.Sprintf("%s.%s", bucketName(bucket), domain))
+}
+
+func stringOrNull(s *string) types.String {
+	if s == nil || *s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(*s)
+}
+
+func stringPointer(s types.String) *string {
+	if s.IsNull() {
+		return nil
+	}
+	return aws.String(s.ValueString())
+}
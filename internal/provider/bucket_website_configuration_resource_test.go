@@ -0,0 +1,150 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBucketWebsiteConfigurationResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfigWebsiteDomain("web.example.com") + testAccBucketWebsiteConfigurationResourceConfig(`
+  index_document_suffix = "index.html"
+  error_document_key    = "404.html"
+
+  routing_rule {
+    condition {
+      key_prefix_equals = "docs/"
+    }
+    redirect {
+      replace_key_prefix_with = "documents/"
+      http_redirect_code      = "302"
+    }
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_bucket_website_configuration.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket_website_configuration.test", "index_document_suffix", "index.html"),
+					resource.TestCheckResourceAttr("rgw_bucket_website_configuration.test", "error_document_key", "404.html"),
+					resource.TestCheckResourceAttr("rgw_bucket_website_configuration.test", "website_endpoint", "test.web.example.com"),
+					resource.TestCheckResourceAttr("rgw_bucket_website_configuration.test", "routing_rule.#", "1"),
+					resource.TestCheckResourceAttr("rgw_bucket_website_configuration.test", "routing_rule.0.condition.key_prefix_equals", "docs/"),
+					resource.TestCheckResourceAttr("rgw_bucket_website_configuration.test", "routing_rule.0.redirect.replace_key_prefix_with", "documents/"),
+					resource.TestCheckResourceAttr("rgw_bucket_website_configuration.test", "routing_rule.0.redirect.http_redirect_code", "302"),
+					testAccCheckBucketSubresource(fake, "test", "website", "documents/"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_bucket_website_configuration.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing, the endpoint is not set without website domain
+			{
+				Config: fake.providerConfig() + testAccBucketWebsiteConfigurationResourceConfig(`
+  redirect_all_requests_to {
+    host_name = "example.com"
+    protocol  = "https"
+  }
+`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("rgw_bucket_website_configuration.test", "index_document_suffix"),
+					resource.TestCheckNoResourceAttr("rgw_bucket_website_configuration.test", "website_endpoint"),
+					resource.TestCheckResourceAttr("rgw_bucket_website_configuration.test", "routing_rule.#", "0"),
+					resource.TestCheckResourceAttr("rgw_bucket_website_configuration.test", "redirect_all_requests_to.host_name", "example.com"),
+					resource.TestCheckResourceAttr("rgw_bucket_website_configuration.test", "redirect_all_requests_to.protocol", "https"),
+					testAccCheckBucketSubresource(fake, "test", "website", "example.com"),
+				),
+			},
+			// Drift testing, deleted configurations are restored
+			{
+				PreConfig: func() {
+					fake.modifyFakeBucket("test", func(bucket *fakeBucket) {
+						delete(bucket.subresources, "website")
+					})
+				},
+				Config: fake.providerConfig() + testAccBucketWebsiteConfigurationResourceConfig(`
+  redirect_all_requests_to {
+    host_name = "example.com"
+    protocol  = "https"
+  }
+`),
+				Check: testAccCheckBucketSubresource(fake, "test", "website", "example.com"),
+			},
+			// Delete testing, the bucket is kept
+			{
+				Config: fake.providerConfig() + testAccBucketConfig,
+				Check:  testAccCheckBucketSubresourceDeleted(fake, "test", "website"),
+			},
+		},
+	})
+}
+
+func TestAccBucketWebsiteConfigurationResource_invalid(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fake.providerConfig() + testAccBucketWebsiteConfigurationResourceConfig(``),
+				ExpectError: regexp.MustCompile(`missing index document`),
+			},
+			{
+				Config: fake.providerConfig() + testAccBucketWebsiteConfigurationResourceConfig(`
+  index_document_suffix = "index.html"
+
+  redirect_all_requests_to {
+    host_name = "example.com"
+  }
+`),
+				ExpectError: regexp.MustCompile(`conflicting website configuration`),
+			},
+			{
+				Config: fake.providerConfig() + testAccBucketWebsiteConfigurationResourceConfig(`
+  index_document_suffix = "index.html"
+
+  routing_rule {
+    condition {
+      http_error_code_returned_equals = "404"
+    }
+  }
+`),
+				ExpectError: regexp.MustCompile(`missing redirect`),
+			},
+		},
+	})
+}
+
+func TestBucketWebsiteConfigurationResource_putMissingRedirect(t *testing.T) {
+	r := &BucketWebsiteConfigurationResource{}
+	data := &BucketWebsiteConfigurationResourceModel{
+		Bucket:              types.StringValue("test"),
+		IndexDocumentSuffix: types.StringValue("index.html"),
+		RoutingRules:        []BucketWebsiteRoutingRuleModel{{}},
+	}
+	if err := r.put(context.Background(), data); err == nil {
+		t.Errorf("expected error for routing rule without redirect")
+	}
+}
+
+func testAccBucketWebsiteConfigurationResourceConfig(website string) string {
+	return testAccBucketConfig + fmt.Sprintf(`
+resource "rgw_bucket_website_configuration" "test" {
+  bucket = rgw_bucket.test.name
+  %s
+}
+`, website)
+}
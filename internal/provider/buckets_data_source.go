@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSourceWithConfigure = &BucketsDataSource{}
+
+func NewBucketsDataSource() datasource.DataSource {
+	return &BucketsDataSource{}
+}
+
+type BucketsDataSource struct {
+	client *RgwClient
+}
+
+type BucketsDataSourceModel struct {
+	Id      types.String `tfsdk:"id"`
+	Tenant  types.String `tfsdk:"tenant"`
+	Prefix  types.String `tfsdk:"prefix"`
+	Buckets []string     `tfsdk:"buckets"`
+}
+
+func (d *BucketsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_buckets"
+}
+
+func (d *BucketsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "List of Buckets in Ceph RGW",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"tenant": schema.StringAttribute{
+				MarkdownDescription: "Only list buckets of this tenant. Set to an empty string to only list buckets without tenant.",
+				Optional:            true,
+			},
+			"prefix": schema.StringAttribute{
+				MarkdownDescription: "Only list buckets whose name (without tenant) starts with this prefix",
+				Optional:            true,
+			},
+			"buckets": schema.ListAttribute{
+				MarkdownDescription: "Sorted bucket names as used in S3 requests (`tenant:bucket` for buckets of a tenant)",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *BucketsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *BucketsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Read Terraform configuration data into the model
+	var data *BucketsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// list buckets, the admin api returns "tenant/bucket" for buckets of a tenant
+	buckets, err := d.client.Admin.ListBuckets(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("could not list buckets", err.Error())
+		return
+	}
+
+	data.Buckets = make([]string, 0)
+	for _, b := range buckets {
+		tenant, name := "", b
+		if splittedName := strings.SplitN(b, "/", 2); len(splittedName) == 2 {
+			tenant, name = splittedName[0], splittedName[1]
+		}
+		if !data.Tenant.IsNull() && tenant != data.Tenant.ValueString() {
+			continue
+		}
+		if !strings.HasPrefix(name, data.Prefix.ValueString()) {
+			continue
+		}
+		if tenant != "" {
+			name = fmt.Sprintf("%s:%s", tenant, name)
+		}
+		data.Buckets = append(data.Buckets, name)
+	}
+	sort.Strings(data.Buckets)
+
+	data.Id = types.StringValue(fmt.Sprintf("%s/%s", data.Tenant.ValueString(), data.Prefix.ValueString()))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
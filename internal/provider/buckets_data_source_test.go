@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBucketsDataSource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fake.providerConfig() + testAccBucketsDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.rgw_buckets.test", "buckets.#", "2"),
+					resource.TestCheckResourceAttr("data.rgw_buckets.test", "buckets.0", "test-a"),
+					resource.TestCheckResourceAttr("data.rgw_buckets.test", "buckets.1", "test-b"),
+				),
+			},
+		},
+	})
+}
+
+const testAccBucketsDataSourceConfig = `
+resource "rgw_bucket" "test_a" {
+  name = "test-a"
+}
+
+resource "rgw_bucket" "test_b" {
+  name = "test-b"
+}
+
+resource "rgw_bucket" "other" {
+  name = "other"
+}
+
+data "rgw_buckets" "test" {
+  tenant = ""
+  prefix = "test-"
+
+  depends_on = [rgw_bucket.test_a, rgw_bucket.test_b, rgw_bucket.other]
+}
+`
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// sharedCredentials reads the keys of a profile from shared credentials and
+// config files in the AWS format. If multiple files contain the profile, the
+// last one takes precedence, and the credentials files take precedence over the
+// config files. Without files, the files from env 'AWS_SHARED_CREDENTIALS_FILE'
+// and 'AWS_CONFIG_FILE' or "~/.aws/credentials" and "~/.aws/config" are read if
+// they exist. The profile defaults to env 'AWS_PROFILE' or "default". ok is
+// false if no file contains the profile.
+func sharedCredentials(ctx context.Context, credentialsFiles []string, configFiles []string, profile string) (value aws.Credentials, ok bool, err error) {
+	// the sdk skips missing files, but configured files must exist
+	for _, file := range append(append([]string{}, credentialsFiles...), configFiles...) {
+		if _, err := os.Stat(file); err != nil {
+			return aws.Credentials{}, false, err
+		}
+	}
+	if len(credentialsFiles) == 0 {
+		credentialsFiles = []string{defaultSharedFile("AWS_SHARED_CREDENTIALS_FILE", config.DefaultSharedCredentialsFilename())}
+	}
+	if len(configFiles) == 0 {
+		configFiles = []string{defaultSharedFile("AWS_CONFIG_FILE", config.DefaultSharedConfigFilename())}
+	}
+
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	shared, err := config.LoadSharedConfigProfile(ctx, profile, func(o *config.LoadSharedConfigOptions) {
+		o.CredentialsFiles = credentialsFiles
+		o.ConfigFiles = configFiles
+	})
+	if err != nil {
+		var notExist config.SharedConfigProfileNotExistError
+		if errors.As(err, &notExist) {
+			return aws.Credentials{}, false, nil
+		}
+		return aws.Credentials{}, false, err
+	}
+	if !shared.Credentials.HasKeys() {
+		return aws.Credentials{}, false, fmt.Errorf("profile %q has no aws_access_key_id and aws_secret_access_key", profile)
+	}
+	return shared.Credentials, true, nil
+}
+
+// defaultSharedFile returns the file from env or the default file.
+func defaultSharedFile(env string, file string) string {
+	if value := os.Getenv(env); value != "" {
+		return value
+	}
+	return file
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSharedCredentials(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first")
+	second := filepath.Join(dir, "second")
+	invalid := filepath.Join(dir, "invalid")
+	config := filepath.Join(dir, "config")
+
+	files := map[string]string{
+		first: `[default]
+aws_access_key_id = first-default
+aws_secret_access_key = first-default-secret
+
+[rgw]
+aws_access_key_id = first-rgw
+aws_secret_access_key = first-rgw-secret
+`,
+		second: `[rgw]
+aws_access_key_id = second-rgw
+aws_secret_access_key = second-rgw-secret
+`,
+		invalid: `[rgw]
+aws_access_key_id = invalid
+`,
+		config: `[default]
+aws_access_key_id = config-default
+aws_secret_access_key = config-default-secret
+
+[profile rgw]
+aws_access_key_id = config-rgw
+aws_secret_access_key = config-rgw-secret
+
+[profile role]
+role_arn = arn:aws:iam::123456789012:role/rgw
+source_profile = default
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := map[string]struct {
+		files     []string
+		config    []string
+		profile   string
+		accessKey string
+		ok        bool
+		err       bool
+	}{
+		"default profile": {
+			files:     []string{first, second},
+			accessKey: "first-default",
+			ok:        true,
+		},
+		"last file takes precedence": {
+			files:     []string{first, second},
+			profile:   "rgw",
+			accessKey: "second-rgw",
+			ok:        true,
+		},
+		"missing profile": {
+			files:   []string{first, second},
+			profile: "missing",
+		},
+		"profile in config file": {
+			config:    []string{config},
+			profile:   "rgw",
+			accessKey: "config-rgw",
+			ok:        true,
+		},
+		"credentials file takes precedence over config file": {
+			files:     []string{first},
+			config:    []string{config},
+			profile:   "rgw",
+			accessKey: "first-rgw",
+			ok:        true,
+		},
+		"missing profile in config file": {
+			config:  []string{config},
+			profile: "first",
+		},
+		"profile without keys": {
+			config:  []string{config},
+			profile: "role",
+			err:     true,
+		},
+		"missing file": {
+			files: []string{filepath.Join(dir, "missing")},
+			err:   true,
+		},
+		"missing config file": {
+			config: []string{filepath.Join(dir, "missing")},
+			err:    true,
+		},
+		"missing secret key": {
+			files:   []string{invalid},
+			profile: "rgw",
+			err:     true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("AWS_PROFILE", "")
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "missing"))
+			t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "missing"))
+
+			value, ok, err := sharedCredentials(context.Background(), c.files, c.config, c.profile)
+			if c.err != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != c.ok {
+				t.Errorf("expected ok to be %t", c.ok)
+			}
+			if value.AccessKeyID != c.accessKey {
+				t.Errorf("expected access key %q, got %q", c.accessKey, value.AccessKeyID)
+			}
+		})
+	}
+}
+
+func TestSharedCredentials_defaultFile(t *testing.T) {
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	// a missing default file is no error
+	if _, ok, err := sharedCredentials(context.Background(), nil, nil, ""); ok || err != nil {
+		t.Errorf("expected no credentials and no error, got %t and %v", ok, err)
+	}
+}
+
+func TestSharedCredentials_defaultConfigFile(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(config, []byte("[profile rgw]\naws_access_key_id = env-config\naws_secret_access_key = env-config-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_PROFILE", "rgw")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("AWS_CONFIG_FILE", config)
+
+	// the profile and the config file are read from env
+	value, ok, err := sharedCredentials(context.Background(), nil, nil, "")
+	if err != nil || !ok || value.AccessKeyID != "env-config" {
+		t.Errorf("expected credentials from env config file, got %q, %t and %v", value.AccessKeyID, ok, err)
+	}
+}
@@ -0,0 +1,1557 @@
+package provider
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+)
+
+// fakeRgw is an in-memory stand-in for the Ceph RGW admin api and the subset of
+// the S3 and SNS api used by the provider, so the acceptance tests do not need a
+// cluster.
+// Authentication is not checked.
+type fakeRgw struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mu      sync.Mutex
+	users   map[string]*admin.User
+	buckets map[string]*fakeBucket
+	topics  map[string]*fakeTopic
+
+	// user of the S3 credentials, owner of buckets created via the S3 api
+	s3User string
+
+	// access keys of the signed requests by api, "admin" or "s3"
+	accessKeys map[string]map[string]bool
+
+	// error codes returned when configuring bucket subresources, e.g. to
+	// simulate a cluster without kms backend
+	rejectedSubresources map[string]string
+}
+
+type fakeBucket struct {
+	info admin.Bucket
+
+	// raw bodies of bucket subresources like "policy" or "tagging"
+	subresources map[string][]byte
+
+	// current versions of the objects by key
+	objects map[string]*fakeObject
+	// noncurrent versions and delete markers of versioned buckets
+	versions []*fakeVersion
+	// multipart uploads by upload id
+	uploads map[string]*fakeUpload
+}
+
+type fakeObject struct {
+	body      []byte
+	etag      string
+	versionId string
+
+	// Content-Type, Cache-Control and X-Amz-Meta-* headers of the upload
+	header http.Header
+	// tags in the form of the x-amz-tagging header
+	tags string
+}
+
+type fakeVersion struct {
+	key       string
+	versionId string
+	// nil for delete markers
+	object *fakeObject
+}
+
+type fakeTagging struct {
+	XMLName xml.Name  `xml:"Tagging"`
+	Tags    []fakeTag `xml:"TagSet>Tag"`
+}
+
+type fakeTag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+type fakeUpload struct {
+	key    string
+	header http.Header
+	tags   string
+	parts  map[int][]byte
+}
+
+type fakeTopic struct {
+	name string
+
+	// attributes as sent in CreateTopic, e.g. "push-endpoint"
+	attributes map[string]string
+}
+
+// bucket subresources stored as sent by the client and the error codes returned
+// if they are not configured.
+var fakeS3Subresources = map[string]string{
+	"policy":       "NoSuchBucketPolicy",
+	"tagging":      "NoSuchTagSet",
+	"lifecycle":    "NoSuchLifecycleConfiguration",
+	"cors":         "NoSuchCORSConfiguration",
+	"object-lock":  "ObjectLockConfigurationNotFoundError",
+	"encryption":   "ServerSideEncryptionConfigurationNotFoundError",
+	"website":      "NoSuchWebsiteConfiguration",
+	"replication":  "ReplicationConfigurationNotFoundError",
+	"versioning":   "",
+	"notification": "",
+	"acl":          "",
+}
+
+// replies of bucket subresources which return an empty configuration if they are
+// not configured.
+var fakeS3EmptySubresources = map[string]string{
+	"versioning":   `<VersioningConfiguration></VersioningConfiguration>`,
+	"notification": `<NotificationConfiguration></NotificationConfiguration>`,
+}
+
+// time format of RGW admin API replies.
+const fakeRgwTimeLayout = "2006-01-02T15:04:05.000000Z"
+
+// maximum number of entries of list replies, small to test the pagination.
+const fakeS3MaxKeys = 3
+
+// default user of the S3 credentials.
+const fakeRgwS3User = "admin"
+
+func newFakeRgw(t *testing.T) *fakeRgw {
+	f := &fakeRgw{
+		t:       t,
+		users:   map[string]*admin.User{},
+		buckets: map[string]*fakeBucket{},
+		topics:  map[string]*fakeTopic{},
+		s3User:  fakeRgwS3User,
+
+		rejectedSubresources: map[string]string{},
+		accessKeys: map[string]map[string]bool{
+			"admin": {},
+			"s3":    {},
+		},
+	}
+	f.users[fakeRgwS3User] = &admin.User{ID: fakeRgwS3User, DisplayName: "Admin"}
+	f.server = httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// useS3User changes the user of the S3 credentials, e.g. to a user of a
+// tenant.
+func (f *fakeRgw) useS3User(uid string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.users[uid] = &admin.User{ID: uid, DisplayName: uid}
+	f.s3User = uid
+}
+
+// providerConfig returns the provider block pointing to the fake.
+func (f *fakeRgw) providerConfig() string {
+	return fmt.Sprintf(`
+provider "rgw" {
+  endpoint   = %q
+  access_key = "test"
+  secret_key = "test"
+}
+`, f.server.URL)
+}
+
+// providerConfigWebsiteDomain returns a provider block with a website domain
+// of the s3website frontend.
+func (f *fakeRgw) providerConfigWebsiteDomain(domain string) string {
+	return fmt.Sprintf(`
+provider "rgw" {
+  endpoint       = %q
+  access_key     = "test"
+  secret_key     = "test"
+  website_domain = %q
+}
+`, f.server.URL, domain)
+}
+
+// providerConfigSeparateEndpoints returns a provider block with separate
+// endpoints and credentials for the admin and S3 api. The shared endpoint is
+// not reachable.
+func (f *fakeRgw) providerConfigSeparateEndpoints() string {
+	adminServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/admin/") {
+			s3Error(w, http.StatusForbidden, "AccessDenied")
+			return
+		}
+		f.handle(w, r)
+	}))
+	f.t.Cleanup(adminServer.Close)
+
+	s3Server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/admin/") {
+			adminError(w, http.StatusForbidden, "AccessDenied")
+			return
+		}
+		f.handle(w, r)
+	}))
+	f.t.Cleanup(s3Server.Close)
+
+	return fmt.Sprintf(`
+provider "rgw" {
+  endpoint       = "http://127.0.0.1:1"
+  admin_endpoint = %q
+  s3_endpoint    = %q
+  access_key     = "admin-key"
+  secret_key     = "admin-secret"
+  max_retries    = 0
+
+  s3_credentials {
+    access_key = "s3-key"
+    secret_key = "s3-secret"
+  }
+}
+`, adminServer.URL, s3Server.URL)
+}
+
+func (f *fakeRgw) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	api := "s3"
+	if strings.HasPrefix(r.URL.Path, "/admin/") {
+		api = "admin"
+	}
+
+	// Authorization: AWS4-HMAC-SHA256 Credential=<access key>/<scope>, ...
+	if _, credential, ok := strings.Cut(r.Header.Get("Authorization"), "Credential="); ok {
+		accessKey, _, _ := strings.Cut(credential, "/")
+		f.accessKeys[api][accessKey] = true
+	}
+
+	if api == "admin" {
+		f.handleAdmin(w, r)
+		return
+	}
+	f.handleS3(w, r)
+}
+
+/*
+ * admin api
+ */
+
+func (f *fakeRgw) handleAdmin(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	switch strings.TrimPrefix(r.URL.Path, "/admin") {
+	case "/user":
+		switch {
+		case hasParam(q, "key"):
+			f.handleUserKey(w, r, q)
+		case hasParam(q, "subuser") && param(q, "subuser") != "":
+			f.handleSubuser(w, r, q)
+		case hasParam(q, "caps"):
+			f.handleUserCaps(w, r, q)
+		case hasParam(q, "quota"):
+			f.handleUserQuota(w, r, q)
+		default:
+			f.handleUser(w, r, q)
+		}
+	case "/bucket":
+		if hasParam(q, "quota") {
+			f.handleBucketQuota(w, r, q)
+			return
+		}
+		f.handleBucket(w, r, q)
+	case "/metadata/user":
+		ids := make([]string, 0, len(f.users))
+		for id := range f.users {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		adminReply(w, ids)
+	default:
+		adminError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (f *fakeRgw) handleUser(w http.ResponseWriter, r *http.Request, q url.Values) {
+	uid := userParam(q)
+
+	if r.Method == http.MethodPut {
+		if _, ok := f.users[uid]; ok {
+			adminError(w, http.StatusConflict, "UserAlreadyExists")
+			return
+		}
+		user := &admin.User{
+			ID:     uid,
+			OpMask: "read, write, delete",
+		}
+		maxBuckets := 1000
+		suspended := 0
+		user.MaxBuckets = &maxBuckets
+		user.Suspended = &suspended
+		f.users[uid] = user
+		f.modifyUser(user, q)
+		if param(q, "generate-key") != "false" {
+			f.addS3Key(user, param(q, "access-key"), param(q, "secret-key"))
+		}
+		adminReply(w, user)
+		return
+	}
+
+	user, ok := f.users[uid]
+	if !ok {
+		adminError(w, http.StatusNotFound, "NoSuchUser")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		adminReply(w, user)
+	case http.MethodPost:
+		f.modifyUser(user, q)
+		if param(q, "generate-key") == "true" {
+			f.addS3Key(user, param(q, "access-key"), param(q, "secret-key"))
+		}
+		adminReply(w, user)
+	case http.MethodDelete:
+		if param(q, "purge-data") == "1" {
+			for name, b := range f.buckets {
+				if b.info.Owner == uid {
+					delete(f.buckets, name)
+				}
+			}
+		}
+		delete(f.users, uid)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// modifyUser applies the user attributes of a create or modify request.
+func (f *fakeRgw) modifyUser(user *admin.User, q url.Values) {
+	if hasParam(q, "display-name") {
+		user.DisplayName = param(q, "display-name")
+	}
+	if hasParam(q, "email") {
+		user.Email = param(q, "email")
+	}
+	if hasParam(q, "op-mask") {
+		user.OpMask = param(q, "op-mask")
+	}
+	if v, err := strconv.Atoi(param(q, "max-buckets")); err == nil {
+		user.MaxBuckets = &v
+	}
+	if v, err := strconv.Atoi(param(q, "suspended")); err == nil {
+		user.Suspended = &v
+	}
+	if hasParam(q, "user-caps") {
+		user.Caps = nil
+		f.addCaps(user, param(q, "user-caps"))
+	}
+}
+
+func (f *fakeRgw) handleUserKey(w http.ResponseWriter, r *http.Request, q url.Values) {
+	user, ok := f.users[userParam(q)]
+	if !ok {
+		adminError(w, http.StatusNotFound, "NoSuchUser")
+		return
+	}
+
+	keyType := param(q, "key-type")
+	if keyType == "" {
+		keyType = "s3"
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if keyType == "swift" {
+			f.addSwiftKey(user, subuserName(user.ID, param(q, "subuser")), param(q, "secret-key"))
+			adminReply(w, user.SwiftKeys)
+			return
+		}
+		accessKey := param(q, "access-key")
+		if f.findS3Key(accessKey) != nil {
+			adminError(w, http.StatusConflict, "KeyExists")
+			return
+		}
+		f.addS3Key(user, accessKey, param(q, "secret-key"))
+		adminReply(w, user.Keys)
+	case http.MethodDelete:
+		accessKey := param(q, "access-key")
+		for i, k := range user.Keys {
+			if k.AccessKey == accessKey {
+				user.Keys = append(user.Keys[:i], user.Keys[i+1:]...)
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+		adminError(w, http.StatusForbidden, "InvalidAccessKeyId")
+	default:
+		adminError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (f *fakeRgw) handleSubuser(w http.ResponseWriter, r *http.Request, q url.Values) {
+	user, ok := f.users[userParam(q)]
+	if !ok {
+		adminError(w, http.StatusNotFound, "NoSuchUser")
+		return
+	}
+
+	name := subuserName(user.ID, param(q, "subuser"))
+	index := -1
+	for i, s := range user.Subusers {
+		if s.Name == name {
+			index = i
+		}
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if index >= 0 {
+			adminError(w, http.StatusConflict, "SubuserExists")
+			return
+		}
+		user.Subusers = append(user.Subusers, admin.SubuserSpec{Name: name, Access: fakeSubuserAccess(param(q, "access"))})
+		if param(q, "generate-secret") == "true" || param(q, "secret-key") != "" {
+			f.addSwiftKey(user, name, param(q, "secret-key"))
+		}
+		adminReply(w, user.Subusers)
+	case http.MethodPost:
+		if index < 0 {
+			adminError(w, http.StatusNotFound, "NoSuchSubUser")
+			return
+		}
+		if hasParam(q, "access") {
+			user.Subusers[index].Access = fakeSubuserAccess(param(q, "access"))
+		}
+		adminReply(w, user.Subusers)
+	case http.MethodDelete:
+		if index < 0 {
+			adminError(w, http.StatusNotFound, "NoSuchSubUser")
+			return
+		}
+		user.Subusers = append(user.Subusers[:index], user.Subusers[index+1:]...)
+		if param(q, "purge-keys") != "false" {
+			swiftKeys := user.SwiftKeys[:0]
+			for _, k := range user.SwiftKeys {
+				if k.User != name {
+					swiftKeys = append(swiftKeys, k)
+				}
+			}
+			user.SwiftKeys = swiftKeys
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		adminError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (f *fakeRgw) handleUserCaps(w http.ResponseWriter, r *http.Request, q url.Values) {
+	user, ok := f.users[userParam(q)]
+	if !ok {
+		adminError(w, http.StatusNotFound, "NoSuchUser")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		f.addCaps(user, param(q, "user-caps"))
+	case http.MethodDelete:
+		for _, c := range parseCaps(param(q, "user-caps")) {
+			caps := user.Caps[:0]
+			for _, existing := range user.Caps {
+				if existing.Type != c.Type {
+					caps = append(caps, existing)
+				}
+			}
+			user.Caps = caps
+		}
+	default:
+		adminError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+		return
+	}
+	adminReply(w, user.Caps)
+}
+
+func (f *fakeRgw) handleUserQuota(w http.ResponseWriter, r *http.Request, q url.Values) {
+	user, ok := f.users[userParam(q)]
+	if !ok {
+		adminError(w, http.StatusNotFound, "NoSuchUser")
+		return
+	}
+
+	quota := &user.UserQuota
+	if param(q, "quota-type") == "bucket" {
+		quota = &user.BucketQuota
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		adminReply(w, quota)
+	case http.MethodPut:
+		setFakeQuota(quota, q)
+		w.WriteHeader(http.StatusOK)
+	default:
+		adminError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (f *fakeRgw) handleBucket(w http.ResponseWriter, r *http.Request, q url.Values) {
+	name := param(q, "bucket")
+
+	if r.Method == http.MethodGet && name == "" {
+		names := make([]string, 0, len(f.buckets))
+		for n := range f.buckets {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		adminReply(w, names)
+		return
+	}
+
+	bucket, ok := f.buckets[name]
+	if !ok {
+		adminError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		adminReply(w, bucket.info)
+	case http.MethodPut:
+		f.linkBucket(w, q, name, bucket)
+	case http.MethodPost:
+		// unlink leaves the bucket without owner
+		if bucket.info.Owner != userParam(q) {
+			adminError(w, http.StatusNotFound, "InvalidArgument")
+			return
+		}
+		bucket.info.Owner = ""
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(f.buckets, name)
+		w.WriteHeader(http.StatusOK)
+	default:
+		adminError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// linkBucket changes the owner of a bucket and moves it into the tenant of the
+// new owner.
+func (f *fakeRgw) linkBucket(w http.ResponseWriter, q url.Values, name string, bucket *fakeBucket) {
+	uid := userParam(q)
+	if _, ok := f.users[uid]; !ok {
+		adminError(w, http.StatusNotFound, "NoSuchUser")
+		return
+	}
+	if id := param(q, "bucket-id"); id != "" && id != bucket.info.ID {
+		adminError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	tenant := userTenant(uid)
+	newName := bucket.info.Bucket
+	if tenant != "" {
+		newName = tenant + "/" + newName
+	}
+	if newName != name {
+		if _, ok := f.buckets[newName]; ok {
+			adminError(w, http.StatusConflict, "BucketAlreadyExists")
+			return
+		}
+		delete(f.buckets, name)
+		f.buckets[newName] = bucket
+	}
+
+	bucket.info.Owner = uid
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *fakeRgw) handleBucketQuota(w http.ResponseWriter, r *http.Request, q url.Values) {
+	if r.Method != http.MethodPut {
+		adminError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+		return
+	}
+
+	// the bucket is given without tenant, the tenant is part of the uid
+	name := param(q, "bucket")
+	if splittedUid := strings.SplitN(userParam(q), "$", 2); len(splittedUid) == 2 {
+		name = splittedUid[0] + "/" + name
+	}
+
+	bucket, ok := f.buckets[name]
+	if !ok {
+		adminError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	setFakeQuota(&bucket.info.BucketQuota, q)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *fakeRgw) addS3Key(user *admin.User, accessKey string, secretKey string) {
+	if accessKey == "" {
+		accessKey = f.randomKey()
+	}
+	if secretKey == "" {
+		secretKey = f.randomKey() + f.randomKey()
+	}
+	user.Keys = append(user.Keys, admin.UserKeySpec{
+		User:      user.ID,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+	})
+}
+
+func (f *fakeRgw) addSwiftKey(user *admin.User, subuser string, secretKey string) {
+	if secretKey == "" {
+		secretKey = f.randomKey() + f.randomKey()
+	}
+	for i, k := range user.SwiftKeys {
+		if k.User == subuser {
+			user.SwiftKeys[i].SecretKey = secretKey
+			return
+		}
+	}
+	user.SwiftKeys = append(user.SwiftKeys, admin.SwiftKeySpec{
+		User:      subuser,
+		SecretKey: secretKey,
+	})
+}
+
+func (f *fakeRgw) findS3Key(accessKey string) *admin.UserKeySpec {
+	if accessKey == "" {
+		return nil
+	}
+	for _, u := range f.users {
+		for i := range u.Keys {
+			if u.Keys[i].AccessKey == accessKey {
+				return &u.Keys[i]
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeRgw) addCaps(user *admin.User, caps string) {
+	for _, c := range parseCaps(caps) {
+		replaced := false
+		for i := range user.Caps {
+			if user.Caps[i].Type == c.Type {
+				user.Caps[i].Perm = c.Perm
+				replaced = true
+			}
+		}
+		if !replaced {
+			user.Caps = append(user.Caps, c)
+		}
+	}
+}
+
+func (f *fakeRgw) randomKey() string {
+	key, err := randomString(accessKeyBytes, accessKeyLength)
+	if err != nil {
+		f.t.Fatalf("could not generate key: %s", err)
+	}
+	return key
+}
+
+// parseCaps parses caps in the format "type=perm;type=perm".
+func parseCaps(caps string) []admin.UserCapSpec {
+	var res []admin.UserCapSpec
+	for _, c := range strings.Split(caps, ";") {
+		splittedCap := strings.SplitN(strings.TrimSpace(c), "=", 2)
+		if len(splittedCap) != 2 {
+			continue
+		}
+		res = append(res, admin.UserCapSpec{Type: splittedCap[0], Perm: splittedCap[1]})
+	}
+	return res
+}
+
+// fakeSubuserAccess converts the access of a request into the reply format.
+func fakeSubuserAccess(access string) admin.SubuserAccess {
+	switch admin.SubuserAccess(access) {
+	case admin.SubuserAccessReadWrite:
+		return admin.SubuserAccessReplyReadWrite
+	case admin.SubuserAccessFull:
+		return admin.SubuserAccessReplyFull
+	}
+	return admin.SubuserAccess(access)
+}
+
+func setFakeQuota(quota *admin.QuotaSpec, q url.Values) {
+	if v, err := strconv.ParseBool(param(q, "enabled")); err == nil {
+		quota.Enabled = &v
+	}
+	if v, err := strconv.ParseInt(param(q, "max-size"), 10, 64); err == nil {
+		quota.MaxSize = &v
+	}
+	if v, err := strconv.ParseInt(param(q, "max-objects"), 10, 64); err == nil {
+		quota.MaxObjects = &v
+	}
+}
+
+func subuserName(uid string, subuser string) string {
+	if strings.Contains(subuser, ":") {
+		return subuser
+	}
+	return uid + ":" + subuser
+}
+
+// userParam returns the uid of a request including the tenant.
+func userParam(q url.Values) string {
+	uid := param(q, "uid")
+	if tenant := param(q, "tenant"); tenant != "" && !strings.Contains(uid, "$") {
+		uid = tenant + "$" + uid
+	}
+	return uid
+}
+
+func hasParam(q url.Values, name string) bool {
+	_, ok := q[name]
+	return ok
+}
+
+// param returns the last non-empty value of a query parameter, the subresource
+// selectors like "?subuser" may also be used as parameters.
+func param(q url.Values, name string) string {
+	values := q[name]
+	for i := len(values) - 1; i >= 0; i-- {
+		if values[i] != "" {
+			return values[i]
+		}
+	}
+	return ""
+}
+
+func adminReply(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func adminError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"Code": code, "RequestId": "fake", "HostId": "fake"})
+}
+
+/*
+ * s3 api
+ */
+
+func (f *fakeRgw) handleS3(w http.ResponseWriter, r *http.Request) {
+	// the SNS api is served by the S3 endpoint
+	if r.URL.Path == "/" && r.Method == http.MethodPost {
+		f.handleSNS(w, r)
+		return
+	}
+
+	q := r.URL.Query()
+
+	bucketName, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucketName == "" {
+		s3Error(w, http.StatusNotImplemented, "NotImplemented")
+		return
+	}
+	// bucket names without tenant refer to the tenant of the S3 credentials
+	name := adminBucketName(bucketName)
+	if tenant := userTenant(f.s3User); tenant != "" && !strings.Contains(name, "/") {
+		name = tenant + "/" + name
+	}
+
+	if key != "" {
+		bucket, ok := f.buckets[name]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchBucket")
+			return
+		}
+		f.handleObject(w, r, bucket, key)
+		return
+	}
+
+	subresource := ""
+	for s := range q {
+		if _, ok := fakeS3Subresources[s]; ok || s == "versions" || s == "uploads" || s == "delete" {
+			subresource = s
+		}
+	}
+
+	if r.Method == http.MethodPut && subresource == "" {
+		f.createBucket(w, r, name)
+		return
+	}
+
+	bucket, ok := f.buckets[name]
+	if !ok {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		s3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	switch subresource {
+	case "":
+		switch r.Method {
+		case http.MethodHead:
+			w.WriteHeader(http.StatusOK)
+		case http.MethodDelete:
+			if len(bucket.objects) > 0 || len(bucket.versions) > 0 || len(bucket.uploads) > 0 {
+				s3Error(w, http.StatusConflict, "BucketNotEmpty")
+				return
+			}
+			delete(f.buckets, name)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			s3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+		}
+	case "versions":
+		listObjectVersions(w, bucket, q)
+	case "uploads":
+		listMultipartUploads(w, bucket, q)
+	case "delete":
+		f.deleteObjects(w, r, bucket)
+	default:
+		f.handleBucketSubresource(w, r, bucket, subresource)
+	}
+}
+
+func (f *fakeRgw) createBucket(w http.ResponseWriter, r *http.Request, name string) {
+	if _, ok := f.buckets[name]; ok {
+		s3Error(w, http.StatusConflict, "BucketAlreadyExists")
+		return
+	}
+
+	var config struct {
+		LocationConstraint string `xml:"LocationConstraint"`
+	}
+	body, _ := io.ReadAll(r.Body)
+	if len(body) > 0 {
+		if err := xml.Unmarshal(body, &config); err != nil {
+			s3Error(w, http.StatusBadRequest, "MalformedXML")
+			return
+		}
+	}
+
+	// location constraint is "<zonegroup>:<placement-target>[/<storage-class>]"
+	placement := "default-placement"
+	if _, target, ok := strings.Cut(config.LocationConstraint, ":"); ok && target != "" {
+		placement = target
+	}
+
+	// the tenant is part of the key of the bucket
+	_, bucketName, ok := strings.Cut(name, "/")
+	if !ok {
+		bucketName = name
+	}
+
+	bucket := &fakeBucket{
+		info: admin.Bucket{
+			Bucket:        bucketName,
+			Owner:         f.s3User,
+			PlacementRule: placement,
+			ID:            f.randomKey(),
+			Mtime:         time.Now().UTC().Format(fakeRgwTimeLayout),
+		},
+		subresources: map[string][]byte{},
+		objects:      map[string]*fakeObject{},
+		uploads:      map[string]*fakeUpload{},
+	}
+	if r.Header.Get("X-Amz-Bucket-Object-Lock-Enabled") == "true" {
+		bucket.subresources["object-lock"] = []byte(`<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled></ObjectLockConfiguration>`)
+		bucket.subresources["versioning"] = []byte(`<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>`)
+	}
+	f.buckets[name] = bucket
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (f *fakeRgw) handleBucketSubresource(w http.ResponseWriter, r *http.Request, bucket *fakeBucket, subresource string) {
+	switch r.Method {
+	case http.MethodGet:
+		body, ok := bucket.subresources[subresource]
+		if !ok && subresource == "acl" {
+			s3Reply(w, fakeCannedAcl(bucket.info.Owner, "private"))
+			return
+		}
+		if !ok {
+			if code := fakeS3Subresources[subresource]; code != "" {
+				s3Error(w, http.StatusNotFound, code)
+				return
+			}
+			s3Reply(w, fakeS3EmptySubresources[subresource])
+			return
+		}
+		_, _ = w.Write(body)
+	case http.MethodPut:
+		if code, ok := f.rejectedSubresources[subresource]; ok {
+			s3Error(w, http.StatusBadRequest, code)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			s3Error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		// RGW rejects grants to users which do not exist
+		if subresource == "acl" {
+			for _, id := range fakeAclGrantees(body) {
+				if _, ok := f.users[id]; !ok {
+					s3Error(w, http.StatusBadRequest, "InvalidArgument")
+					return
+				}
+			}
+		}
+		// canned acls are sent as header, private is the default acl
+		if canned := r.Header.Get("X-Amz-Acl"); subresource == "acl" && canned != "" {
+			if canned == "private" {
+				delete(bucket.subresources, subresource)
+			} else {
+				bucket.subresources[subresource] = []byte(fakeCannedAcl(bucket.info.Owner, canned))
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		// an empty notification configuration deletes all notifications
+		if subresource == "notification" && !strings.Contains(string(body), "<TopicConfiguration>") {
+			delete(bucket.subresources, subresource)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if subresource == "replication" {
+			body = fakeReplicationRuleIds(body)
+		}
+		bucket.subresources[subresource] = body
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(bucket.subresources, subresource)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+func (f *fakeRgw) handleObject(w http.ResponseWriter, r *http.Request, bucket *fakeBucket, key string) {
+	q := r.URL.Query()
+
+	// multipart uploads
+	if q.Has("uploads") && r.Method == http.MethodPost {
+		id := f.randomKey()
+		bucket.uploads[id] = &fakeUpload{
+			key:    key,
+			header: fakeObjectHeader(r),
+			tags:   r.Header.Get("X-Amz-Tagging"),
+			parts:  map[int][]byte{},
+		}
+		s3Reply(w, fmt.Sprintf(`<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>`, bucket.info.Bucket, key, id))
+		return
+	}
+	if q.Has("uploadId") {
+		upload, ok := bucket.uploads[q.Get("uploadId")]
+		if !ok || upload.key != key {
+			s3Error(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			partNumber, err := strconv.Atoi(q.Get("partNumber"))
+			if err != nil || partNumber < 1 {
+				s3Error(w, http.StatusBadRequest, "InvalidArgument")
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			upload.parts[partNumber] = body
+			w.Header().Set("ETag", fmt.Sprintf(`"%x"`, md5.Sum(body)))
+			w.WriteHeader(http.StatusOK)
+		case http.MethodPost:
+			var content, sums []byte
+			for i := 1; i <= len(upload.parts); i++ {
+				part, ok := upload.parts[i]
+				if !ok {
+					s3Error(w, http.StatusBadRequest, "InvalidPart")
+					return
+				}
+				if i < len(upload.parts) && len(part) < s3MinPartSize {
+					s3Error(w, http.StatusBadRequest, "EntityTooSmall")
+					return
+				}
+				sum := md5.Sum(part)
+				content = append(content, part...)
+				sums = append(sums, sum[:]...)
+			}
+			etag := fmt.Sprintf("%x-%d", md5.Sum(sums), len(upload.parts))
+			f.putObject(bucket, key, &fakeObject{body: content, etag: etag, header: upload.header, tags: upload.tags})
+			delete(bucket.uploads, q.Get("uploadId"))
+			s3Reply(w, fmt.Sprintf(`<CompleteMultipartUploadResult><Key>%s</Key><ETag>"%s"</ETag></CompleteMultipartUploadResult>`, key, etag))
+		case http.MethodDelete:
+			delete(bucket.uploads, q.Get("uploadId"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			s3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+		}
+		return
+	}
+
+	if r.Method == http.MethodPut && !q.Has("tagging") {
+		body, _ := io.ReadAll(r.Body)
+		object := &fakeObject{body: body, etag: fmt.Sprintf("%x", md5.Sum(body)), header: fakeObjectHeader(r), tags: r.Header.Get("X-Amz-Tagging")}
+		f.putObject(bucket, key, object)
+		w.Header().Set("ETag", fmt.Sprintf(`"%s"`, object.etag))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if r.Method == http.MethodDelete && !q.Has("tagging") {
+		f.deleteObject(bucket, key, q.Get("versionId"))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	object, ok := bucket.objects[key]
+	if !ok {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		s3Error(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+
+	if q.Has("tagging") {
+		var tagging fakeTagging
+		switch r.Method {
+		case http.MethodGet:
+			tags, _ := url.ParseQuery(object.tags)
+			keys := make([]string, 0, len(tags))
+			for k := range tags {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				tagging.Tags = append(tagging.Tags, fakeTag{Key: k, Value: tags.Get(k)})
+			}
+			body, _ := xml.Marshal(tagging)
+			s3Reply(w, string(body))
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			if err := xml.Unmarshal(body, &tagging); err != nil {
+				s3Error(w, http.StatusBadRequest, "MalformedXML")
+				return
+			}
+			tags := url.Values{}
+			for _, tag := range tagging.Tags {
+				tags.Set(tag.Key, tag.Value)
+			}
+			object.tags = tags.Encode()
+			w.WriteHeader(http.StatusOK)
+		default:
+			s3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodHead, http.MethodGet:
+		for k, v := range object.header {
+			w.Header()[k] = v
+		}
+		w.Header().Set("ETag", fmt.Sprintf(`"%s"`, object.etag))
+		w.Header().Set("Content-Length", strconv.Itoa(len(object.body)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(object.body)
+		}
+	default:
+		s3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// putObject stores the object as current version, the previous version is kept
+// in versioned buckets.
+func (f *fakeRgw) putObject(bucket *fakeBucket, key string, object *fakeObject) {
+	object.versionId = "null"
+	if bucket.versioned() {
+		object.versionId = f.randomKey()
+		if current, ok := bucket.objects[key]; ok {
+			bucket.versions = append(bucket.versions, &fakeVersion{key: key, versionId: current.versionId, object: current})
+		}
+	}
+	bucket.objects[key] = object
+}
+
+// deleteObject deletes a version of an object. Without version id, versioned
+// buckets keep the current version and add a delete marker.
+func (f *fakeRgw) deleteObject(bucket *fakeBucket, key string, versionId string) {
+	current, ok := bucket.objects[key]
+	if versionId == "" {
+		if bucket.versioned() {
+			if ok {
+				bucket.versions = append(bucket.versions, &fakeVersion{key: key, versionId: current.versionId, object: current})
+			}
+			bucket.versions = append(bucket.versions, &fakeVersion{key: key, versionId: f.randomKey()})
+		}
+		delete(bucket.objects, key)
+		return
+	}
+
+	if ok && current.versionId == versionId {
+		delete(bucket.objects, key)
+		// the latest noncurrent version becomes current, unless it is a delete marker
+		for i := len(bucket.versions) - 1; i >= 0; i-- {
+			if v := bucket.versions[i]; v.key == key {
+				if v.object != nil {
+					bucket.objects[key] = v.object
+					bucket.versions = append(bucket.versions[:i], bucket.versions[i+1:]...)
+				}
+				break
+			}
+		}
+		return
+	}
+	for i, v := range bucket.versions {
+		if v.key == key && v.versionId == versionId {
+			bucket.versions = append(bucket.versions[:i], bucket.versions[i+1:]...)
+			return
+		}
+	}
+}
+
+func (f *fakeRgw) deleteObjects(w http.ResponseWriter, r *http.Request, bucket *fakeBucket) {
+	var request struct {
+		Objects []struct {
+			Key       string `xml:"Key"`
+			VersionId string `xml:"VersionId"`
+		} `xml:"Object"`
+		Quiet bool `xml:"Quiet"`
+	}
+	body, _ := io.ReadAll(r.Body)
+	if err := xml.Unmarshal(body, &request); err != nil {
+		s3Error(w, http.StatusBadRequest, "MalformedXML")
+		return
+	}
+
+	var deleted strings.Builder
+	for _, o := range request.Objects {
+		f.deleteObject(bucket, o.Key, o.VersionId)
+		if !request.Quiet {
+			fmt.Fprintf(&deleted, "<Deleted><Key>%s</Key><VersionId>%s</VersionId></Deleted>", o.Key, o.VersionId)
+		}
+	}
+	s3Reply(w, "<DeleteResult>"+deleted.String()+"</DeleteResult>")
+}
+
+// listObjectVersions lists current and noncurrent versions and delete markers
+// ordered by key and version id, starting after the markers.
+func listObjectVersions(w http.ResponseWriter, bucket *fakeBucket, q url.Values) {
+	versions := make([]*fakeVersion, 0, len(bucket.objects)+len(bucket.versions))
+	for key, object := range bucket.objects {
+		versions = append(versions, &fakeVersion{key: key, versionId: object.versionId, object: object})
+	}
+	versions = append(versions, bucket.versions...)
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].key != versions[j].key {
+			return versions[i].key < versions[j].key
+		}
+		return versions[i].versionId < versions[j].versionId
+	})
+
+	var entries strings.Builder
+	count := 0
+	truncated := false
+	var last *fakeVersion
+	for _, v := range versions {
+		if !fakeAfterMarkers(v.key, v.versionId, q.Get("key-marker"), q.Get("version-id-marker")) {
+			continue
+		}
+		if count == fakeS3MaxKeys {
+			truncated = true
+			break
+		}
+		current := bucket.objects[v.key] != nil && bucket.objects[v.key].versionId == v.versionId
+		element := "Version"
+		if v.object == nil {
+			element = "DeleteMarker"
+		}
+		fmt.Fprintf(&entries, "<%[1]s><Key>%[2]s</Key><VersionId>%[3]s</VersionId><IsLatest>%[4]t</IsLatest></%[1]s>", element, v.key, v.versionId, current)
+		count++
+		last = v
+	}
+	if truncated {
+		fmt.Fprintf(&entries, "<NextKeyMarker>%s</NextKeyMarker><NextVersionIdMarker>%s</NextVersionIdMarker>", last.key, last.versionId)
+	}
+	s3Reply(w, fmt.Sprintf("<ListVersionsResult><IsTruncated>%t</IsTruncated>%s</ListVersionsResult>", truncated, entries.String()))
+}
+
+// listMultipartUploads lists the multipart uploads ordered by key and upload id,
+// starting after the markers.
+func listMultipartUploads(w http.ResponseWriter, bucket *fakeBucket, q url.Values) {
+	ids := make([]string, 0, len(bucket.uploads))
+	for id := range bucket.uploads {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := bucket.uploads[ids[i]], bucket.uploads[ids[j]]
+		if a.key != b.key {
+			return a.key < b.key
+		}
+		return ids[i] < ids[j]
+	})
+
+	var entries strings.Builder
+	count := 0
+	truncated := false
+	lastKey, lastId := "", ""
+	for _, id := range ids {
+		key := bucket.uploads[id].key
+		if !fakeAfterMarkers(key, id, q.Get("key-marker"), q.Get("upload-id-marker")) {
+			continue
+		}
+		if count == fakeS3MaxKeys {
+			truncated = true
+			break
+		}
+		fmt.Fprintf(&entries, "<Upload><Key>%s</Key><UploadId>%s</UploadId></Upload>", key, id)
+		count++
+		lastKey, lastId = key, id
+	}
+	if truncated {
+		fmt.Fprintf(&entries, "<NextKeyMarker>%s</NextKeyMarker><NextUploadIdMarker>%s</NextUploadIdMarker>", lastKey, lastId)
+	}
+	s3Reply(w, fmt.Sprintf("<ListMultipartUploadsResult><IsTruncated>%t</IsTruncated>%s</ListMultipartUploadsResult>", truncated, entries.String()))
+}
+
+// fakeAfterMarkers returns whether an entry of a list comes after the markers
+// of the previous page. Without id marker all entries of the key marker are
+// skipped.
+func fakeAfterMarkers(key string, id string, keyMarker string, idMarker string) bool {
+	if key != keyMarker {
+		return key > keyMarker
+	}
+	return idMarker != "" && id > idMarker
+}
+
+func (b *fakeBucket) versioned() bool {
+	return strings.Contains(string(b.subresources["versioning"]), "<Status>Enabled</Status>")
+}
+
+// fakeObjectHeader returns the headers of an upload stored with the object.
+func fakeObjectHeader(r *http.Request) http.Header {
+	header := http.Header{}
+	for k, v := range r.Header {
+		if k == "Content-Type" || k == "Cache-Control" || strings.HasPrefix(k, "X-Amz-Meta-") {
+			header[k] = v
+		}
+	}
+	return header
+}
+
+// fakeReplicationRuleIds adds ids to replication rules without id, like RGW
+// does when converting the rules into sync pipes.
+func fakeReplicationRuleIds(body []byte) []byte {
+	rules := strings.Split(string(body), "<Rule>")
+	for i := 1; i < len(rules); i++ {
+		rule, _, _ := strings.Cut(rules[i], "</Rule>")
+		if !strings.Contains(rule, "<ID>") {
+			rules[i] = fmt.Sprintf("<ID>generated-%d</ID>", i) + rules[i]
+		}
+	}
+	return []byte(strings.Join(rules, "<Rule>"))
+}
+
+// fakeAclGrantees returns the user ids of the grants of an access control
+// policy.
+func fakeAclGrantees(body []byte) []string {
+	var policy struct {
+		Grants []struct {
+			ID string `xml:"Grantee>ID"`
+		} `xml:"AccessControlList>Grant"`
+	}
+	_ = xml.Unmarshal(body, &policy)
+	var ids []string
+	for _, grant := range policy.Grants {
+		if grant.ID != "" {
+			ids = append(ids, grant.ID)
+		}
+	}
+	return ids
+}
+
+// fakeCannedAcl returns the access control policy of a canned acl.
+func fakeCannedAcl(owner string, canned string) string {
+	grant := func(granteeType string, grantee string, permission string) string {
+		return fmt.Sprintf(`<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="%s">%s</Grantee><Permission>%s</Permission></Grant>`, granteeType, grantee, permission)
+	}
+	allUsers := `<URI>http://acs.amazonaws.com/groups/global/AllUsers</URI>`
+
+	grants := grant("CanonicalUser", fmt.Sprintf("<ID>%s</ID>", owner), "FULL_CONTROL")
+	switch canned {
+	case "public-read":
+		grants += grant("Group", allUsers, "READ")
+	case "public-read-write":
+		grants += grant("Group", allUsers, "READ") + grant("Group", allUsers, "WRITE")
+	case "authenticated-read":
+		grants += grant("Group", `<URI>http://acs.amazonaws.com/groups/global/AuthenticatedUsers</URI>`, "READ")
+	}
+	return fmt.Sprintf(`<AccessControlPolicy><Owner><ID>%s</ID></Owner><AccessControlList>%s</AccessControlList></AccessControlPolicy>`, owner, grants)
+}
+
+func s3Reply(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = io.WriteString(w, xml.Header+body)
+}
+
+func s3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, "%s<Error><Code>%s</Code><Message>%s</Message><RequestId>fake</RequestId></Error>", xml.Header, code, code)
+}
+
+/*
+ * sns api
+ */
+
+func (f *fakeRgw) handleSNS(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		snsError(w, http.StatusBadRequest, "InvalidParameter")
+		return
+	}
+
+	switch action := r.Form.Get("Action"); action {
+	case "CreateTopic":
+		topic := &fakeTopic{
+			name:       r.Form.Get("Name"),
+			attributes: map[string]string{},
+		}
+		for i := 1; r.Form.Has(fmt.Sprintf("Attributes.entry.%d.key", i)); i++ {
+			key := r.Form.Get(fmt.Sprintf("Attributes.entry.%d.key", i))
+			topic.attributes[key] = r.Form.Get(fmt.Sprintf("Attributes.entry.%d.value", i))
+		}
+		arn := fakeTopicArn(topic.name)
+		f.topics[arn] = topic
+		snsReply(w, action, "<TopicArn>"+arn+"</TopicArn>")
+	case "GetTopicAttributes":
+		arn := r.Form.Get("TopicArn")
+		topic, ok := f.topics[arn]
+		if !ok {
+			snsError(w, http.StatusNotFound, "NotFound")
+			return
+		}
+		endpoint, _ := json.Marshal(map[string]interface{}{
+			"EndpointAddress": topic.attributes["push-endpoint"],
+			"EndpointArgs":    "",
+			"EndpointTopic":   topic.name,
+			"HasStoredSecret": false,
+			"Persistent":      topic.attributes["persistent"] == "true",
+		})
+		attributes := map[string]string{
+			"User":       f.s3User,
+			"Name":       topic.name,
+			"EndPoint":   string(endpoint),
+			"TopicArn":   arn,
+			"OpaqueData": topic.attributes["OpaqueData"],
+		}
+		var entries strings.Builder
+		entries.WriteString("<Attributes>")
+		for key, value := range attributes {
+			entries.WriteString("<entry><key>" + key + "</key><value>")
+			_ = xml.EscapeText(&entries, []byte(value))
+			entries.WriteString("</value></entry>")
+		}
+		entries.WriteString("</Attributes>")
+		snsReply(w, action, entries.String())
+	case "DeleteTopic":
+		delete(f.topics, r.Form.Get("TopicArn"))
+		snsReply(w, action, "")
+	default:
+		snsError(w, http.StatusBadRequest, "InvalidAction")
+	}
+}
+
+// fakeTopicArn returns the arn of a topic of the S3 user in the zonegroup "default".
+func fakeTopicArn(name string) string {
+	return "arn:aws:sns:default::" + name
+}
+
+func snsReply(w http.ResponseWriter, action string, result string) {
+	w.Header().Set("Content-Type", "text/xml")
+	_, _ = fmt.Fprintf(w, `%s<%[2]sResponse xmlns="https://sns.amazonaws.com/doc/2010-03-31/"><%[2]sResult>%[3]s</%[2]sResult><ResponseMetadata><RequestId>fake</RequestId></ResponseMetadata></%[2]sResponse>`, xml.Header, action, result)
+}
+
+func snsError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, "%s<ErrorResponse><Error><Type>Sender</Type><Code>%s</Code><Message>%s</Message></Error><RequestId>fake</RequestId></ErrorResponse>", xml.Header, code, code)
+}
+
+/*
+ * helpers for tests to modify the state of the fake, e.g. to simulate drift
+ */
+
+// usedAccessKey checks whether a request to the api was signed with the key.
+func (f *fakeRgw) usedAccessKey(api string, accessKey string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.accessKeys[api][accessKey]
+}
+
+func (f *fakeRgw) user(uid string) *admin.User {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.users[uid]
+}
+
+func (f *fakeRgw) modifyFakeUser(uid string, modify func(user *admin.User)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	user, ok := f.users[uid]
+	if !ok {
+		f.t.Fatalf("fake rgw: no user '%s'", uid)
+	}
+	modify(user)
+}
+
+func (f *fakeRgw) deleteUser(uid string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.users, uid)
+}
+
+func (f *fakeRgw) bucket(name string) *fakeBucket {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.buckets[adminBucketName(name)]
+}
+
+func (f *fakeRgw) modifyFakeBucket(name string, modify func(bucket *fakeBucket)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	bucket, ok := f.buckets[adminBucketName(name)]
+	if !ok {
+		f.t.Fatalf("fake rgw: no bucket '%s'", name)
+	}
+	modify(bucket)
+}
+
+func (f *fakeRgw) deleteBucket(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.buckets, adminBucketName(name))
+}
+
+// addFakeBucket adds a bucket ("bucket" or "tenant/bucket") not managed by terraform.
+func (f *fakeRgw) addFakeBucket(name string, owner string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, bucketName, ok := strings.Cut(adminBucketName(name), "/")
+	if !ok {
+		bucketName = name
+	}
+	f.buckets[adminBucketName(name)] = &fakeBucket{
+		info: admin.Bucket{
+			Bucket:        bucketName,
+			Owner:         owner,
+			PlacementRule: "default-placement",
+			ID:            f.randomKey(),
+			Mtime:         time.Now().UTC().Format(fakeRgwTimeLayout),
+		},
+		subresources: map[string][]byte{},
+		objects:      map[string]*fakeObject{},
+		uploads:      map[string]*fakeUpload{},
+	}
+}
+
+func (f *fakeRgw) object(bucket string, key string) *fakeObject {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	b, ok := f.buckets[adminBucketName(bucket)]
+	if !ok {
+		return nil
+	}
+	return b.objects[key]
+}
+
+func (f *fakeRgw) modifyFakeObject(bucket string, key string, modify func(object *fakeObject)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	b, ok := f.buckets[adminBucketName(bucket)]
+	if !ok || b.objects[key] == nil {
+		f.t.Fatalf("fake rgw: no object '%s' in bucket '%s'", key, bucket)
+	}
+	modify(b.objects[key])
+}
+
+// putFakeObject uploads an object not managed by terraform.
+func (f *fakeRgw) putFakeObject(bucket string, key string, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	b, ok := f.buckets[adminBucketName(bucket)]
+	if !ok {
+		f.t.Fatalf("fake rgw: no bucket '%s'", bucket)
+	}
+	f.putObject(b, key, &fakeObject{body: []byte(body), etag: fmt.Sprintf("%x", md5.Sum([]byte(body))), header: http.Header{}})
+}
+
+// deleteFakeObject deletes an object like a DeleteObject request without
+// version id.
+func (f *fakeRgw) deleteFakeObject(bucket string, key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	b, ok := f.buckets[adminBucketName(bucket)]
+	if !ok {
+		f.t.Fatalf("fake rgw: no bucket '%s'", bucket)
+	}
+	f.deleteObject(b, key, "")
+}
+
+// startFakeUpload starts a multipart upload which is never completed.
+func (f *fakeRgw) startFakeUpload(bucket string, key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	b, ok := f.buckets[adminBucketName(bucket)]
+	if !ok {
+		f.t.Fatalf("fake rgw: no bucket '%s'", bucket)
+	}
+	b.uploads[f.randomKey()] = &fakeUpload{key: key, header: http.Header{}, parts: map[int][]byte{}}
+}
+
+func (f *fakeRgw) topic(arn string) *fakeTopic {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.topics[arn]
+}
+
+func (f *fakeRgw) modifyFakeTopic(arn string, modify func(topic *fakeTopic)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	topic, ok := f.topics[arn]
+	if !ok {
+		f.t.Fatalf("fake rgw: no topic '%s'", arn)
+	}
+	modify(topic)
+}
+
+func (f *fakeRgw) deleteTopic(arn string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.topics, arn)
+}
+
+// rejectSubresource lets all requests configuring the bucket subresource fail
+// with the error code.
+func (f *fakeRgw) rejectSubresource(subresource string, code string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rejectedSubresources[subresource] = code
+}
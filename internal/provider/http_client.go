@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// default number of retries of failed requests
+	defaultMaxRetries = 5
+
+	// default upper limit of the wait time between retries
+	defaultRetryMaxBackoff = 20 * time.Second
+
+	// default timeout of a single request
+	defaultRequestTimeout = 5 * time.Minute
+
+	// wait time before the first retry, doubled for every further retry
+	retryMinBackoff = 200 * time.Millisecond
+
+	// request bodies up to this size are buffered so they can be sent again,
+	// larger requests are not retried
+	maxRetryBodySize = 16 << 20
+)
+
+// httpClientConfig configures the http client shared by the admin and S3 clients.
+type httpClientConfig struct {
+	// PEM encoded CA certificates, added to the system pool
+	CACertPEM []byte
+
+	// PEM encoded client certificate and key
+	ClientCertPEM []byte
+	ClientKeyPEM  []byte
+
+	InsecureSkipVerify bool
+
+	// number of retries of failed requests
+	MaxRetries int
+
+	// upper limit of the wait time between retries
+	RetryMaxBackoff time.Duration
+
+	// timeout of a single request, zero means no timeout
+	RequestTimeout time.Duration
+}
+
+// newHTTPClient creates the http client used for all requests to RGW.
+func newHTTPClient(config httpClientConfig) (*http.Client, error) {
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type of default transport: %T", http.DefaultTransport)
+	}
+	transport := defaultTransport.Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{
+		Transport: &retryTransport{
+			next:       transport,
+			maxRetries: config.MaxRetries,
+			maxBackoff: config.RetryMaxBackoff,
+			timeout:    config.RequestTimeout,
+		},
+	}, nil
+}
+
+func newTLSConfig(config httpClientConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	// add custom ca certificates to the system pool
+	if len(config.CACertPEM) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(config.CACertPEM) {
+			return nil, errors.New("no valid PEM encoded certificate found in CA certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	// client certificate for mutual tls
+	if len(config.ClientCertPEM) > 0 || len(config.ClientKeyPEM) > 0 {
+		cert, err := tls.X509KeyPair(config.ClientCertPEM, config.ClientKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate or key: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// retryTransport retries requests failing with connection errors or responses
+// of an overloaded or unavailable RGW, e.g. "503 SlowDown". Requests which may
+// have been processed by RGW are only retried if repeating them is safe.
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+	maxBackoff time.Duration
+	timeout    time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	maxRetries := t.maxRetries
+
+	// buffer the body, so it can be sent again
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		if req.ContentLength < 0 || req.ContentLength > maxRetryBodySize {
+			maxRetries = 0
+		} else {
+			body, err := io.ReadAll(req.Body)
+			req.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+			req.Body, _ = req.GetBody()
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp, err := t.roundTrip(req)
+		if attempt >= maxRetries || !retryable(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if err != nil {
+			tflog.Debug(req.Context(), "retrying failed request", map[string]interface{}{"method": req.Method, "url": req.URL.Redacted(), "attempt": attempt + 1, "wait": wait.String(), "error": err.Error()})
+		} else {
+			tflog.Debug(req.Context(), "retrying failed request", map[string]interface{}{"method": req.Method, "url": req.URL.Redacted(), "attempt": attempt + 1, "wait": wait.String(), "status": resp.StatusCode})
+			// discard the response to reuse the connection
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// roundTrip sends a single request, limited by the request timeout.
+func (t *retryTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// the timeout also applies to reading the body
+	resp.Body = &cancelReadCloser{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// backoff returns the wait time before a retry, which grows exponentially with
+// full jitter. A Retry-After header of the response is respected.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	maxBackoff := t.maxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+
+	backoff := retryMinBackoff << attempt
+	if backoff <= 0 || backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	// #nosec G404 -- jitter does not need a secure random number
+	wait := time.Duration(rand.Int63n(int64(backoff)) + 1)
+
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter := time.Duration(seconds) * time.Second
+			if retryAfter > maxBackoff {
+				retryAfter = maxBackoff
+			}
+			if retryAfter > wait {
+				wait = retryAfter
+			}
+		}
+	}
+
+	return wait
+}
+
+// retryable decides whether a failed request may be sent again.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// do not retry if terraform was interrupted
+		if req.Context().Err() != nil {
+			return false
+		}
+		// the request may have been processed before the connection failed
+		return idempotent(req)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		// the request was rejected without processing it
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent(req)
+	}
+	return false
+}
+
+// idempotent checks whether a request can be repeated without side effects.
+// Admin api requests creating or modifying users and keys are not idempotent,
+// e.g. a repeated request may generate another key.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodDelete:
+		return true
+	case http.MethodPut:
+		return !strings.Contains(req.URL.Path, "/admin/")
+	}
+	return false
+}
+
+// cancelReadCloser cancels the context of a request when its body is closed.
+type cancelReadCloser struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelReadCloser) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	cases := map[string]struct {
+		config  httpClientConfig
+		success bool
+	}{
+		"unknown ca": {
+			config:  httpClientConfig{},
+			success: false,
+		},
+		"custom ca": {
+			config:  httpClientConfig{CACertPEM: caCert},
+			success: true,
+		},
+		"insecure": {
+			config:  httpClientConfig{InsecureSkipVerify: true},
+			success: true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			client, err := newHTTPClient(c.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if c.success && err != nil {
+				t.Errorf("expected request to succeed, got %s", err)
+			}
+			if !c.success && err == nil {
+				t.Errorf("expected request to fail")
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_invalid(t *testing.T) {
+	if _, err := newHTTPClient(httpClientConfig{CACertPEM: []byte("invalid")}); err == nil {
+		t.Errorf("expected error for invalid ca certificate")
+	}
+	if _, err := newHTTPClient(httpClientConfig{ClientCertPEM: []byte("invalid"), ClientKeyPEM: []byte("invalid")}); err == nil {
+		t.Errorf("expected error for invalid client certificate")
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	cases := map[string]struct {
+		method   string
+		path     string
+		status   int
+		attempts int32
+	}{
+		"slow down": {
+			method:   http.MethodPut,
+			path:     "/bucket",
+			status:   http.StatusServiceUnavailable,
+			attempts: 3,
+		},
+		"too many requests": {
+			method:   http.MethodPost,
+			path:     "/admin/user",
+			status:   http.StatusTooManyRequests,
+			attempts: 3,
+		},
+		"internal error of idempotent request": {
+			method:   http.MethodGet,
+			path:     "/admin/user",
+			status:   http.StatusInternalServerError,
+			attempts: 3,
+		},
+		"internal error of admin put request": {
+			method:   http.MethodPut,
+			path:     "/admin/user",
+			status:   http.StatusInternalServerError,
+			attempts: 1,
+		},
+		"client error": {
+			method:   http.MethodGet,
+			path:     "/bucket",
+			status:   http.StatusNotFound,
+			attempts: 1,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if r.Method != http.MethodGet && string(body) != "body" {
+					t.Errorf("unexpected body %q in attempt %d", body, attempts+1)
+				}
+				// fail twice
+				if atomic.AddInt32(&attempts, 1) <= 2 {
+					w.WriteHeader(c.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client, err := newHTTPClient(httpClientConfig{MaxRetries: 5, RetryMaxBackoff: time.Millisecond})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var body io.Reader
+			if c.method != http.MethodGet {
+				// hide the type, so the body can not be replayed by GetBody
+				body = io.MultiReader(strings.NewReader("body"))
+			}
+			req, err := http.NewRequest(c.method, server.URL+c.path, body)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			req.ContentLength = 4
+			if body == nil {
+				req.ContentLength = 0
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			resp.Body.Close()
+
+			if attempts != c.attempts {
+				t.Errorf("expected %d attempts, got %d", c.attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_maxRetries(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := newHTTPClient(httpClientConfig{MaxRetries: 2, RetryMaxBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected last response to be returned, got status %d", resp.StatusCode)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryTransport_timeout(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// hang in the first attempt
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client, err := newHTTPClient(httpClientConfig{MaxRetries: 1, RetryMaxBackoff: time.Millisecond, RequestTimeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("unexpected error reading body: %s", err)
+	}
+
+	if string(body) != "ok" || attempts != 2 {
+		t.Errorf("expected the timed out request to be retried, got %q after %d attempts", body, attempts)
+	}
+}
+
+func TestRetryTransport_backoff(t *testing.T) {
+	transport := &retryTransport{maxBackoff: time.Second}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		if wait := transport.backoff(attempt, nil); wait <= 0 || wait > time.Second {
+			t.Errorf("attempt %d: wait %s out of range", attempt, wait)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"60"}}}
+	if wait := transport.backoff(0, resp); wait != time.Second {
+		t.Errorf("expected Retry-After to be capped to 1s, got %s", wait)
+	}
+}
@@ -116,3 +116,27 @@ func (m testModifier) PlanModifyString(ctx context.Context, req planmodifier.Str
 	ctx = tflog.SetField(ctx, "__generate_credentials_state_unknown", state.IsUnknown())
 	tflog.Info(ctx, "no doing anything")
 }
+
+// policyDocumentModifier keeps the policy document of the state if the
+// configured document is semantically equal, e.g. only differs in formatting.
+type policyDocumentModifier struct{}
+
+func (m policyDocumentModifier) Description(ctx context.Context) string {
+	return "Ignores changes of the policy document which do not change its meaning"
+}
+
+func (m policyDocumentModifier) MarkdownDescription(ctx context.Context) string {
+	return "Ignores changes of the policy document which do not change its meaning"
+}
+
+func (m policyDocumentModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.StateValue.IsNull() || req.PlanValue.IsNull() || req.PlanValue.IsUnknown() {
+		return
+	}
+
+	equal, diags := PolicyDocumentValue{StringValue: req.StateValue}.StringSemanticEquals(ctx, req.PlanValue)
+	resp.Diagnostics.Append(diags...)
+	if equal {
+		resp.PlanValue = req.StateValue
+	}
+}
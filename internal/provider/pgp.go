@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// readPGPPublicKey parses an ASCII armored or base64 encoded binary PGP public key.
+func readPGPPublicKey(publicKey string) (*openpgp.Entity, error) {
+	var entities openpgp.EntityList
+	var err error
+
+	publicKey = strings.TrimSpace(publicKey)
+	if strings.HasPrefix(publicKey, "-----BEGIN") {
+		entities, err = openpgp.ReadArmoredKeyRing(strings.NewReader(publicKey))
+	} else {
+		var key []byte
+		key, err = base64.StdEncoding.DecodeString(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("key is neither ASCII armored nor base64 encoded: %w", err)
+		}
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(key))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entities) != 1 {
+		return nil, fmt.Errorf("expected exactly one public key, got %d", len(entities))
+	}
+	return entities[0], nil
+}
+
+// encryptSecret encrypts a secret for the PGP public key. The result is the
+// base64 encoded binary message, which can be decrypted with
+// `base64 --decode | gpg --decrypt`.
+func encryptSecret(publicKey string, secret string) (string, error) {
+	entity, err := readPGPPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	w, err := openpgp.Encrypt(&buf, []*openpgp.Entity{entity}, nil, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write([]byte(secret)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// protectSecretKey returns the secret key and its encrypted form to be saved in
+// the state. If a pgp key is set, only the encrypted secret key is saved. A known
+// encrypted secret key is kept, as every encryption returns a different result.
+func protectSecretKey(pgpKey types.String, secretKey types.String, encryptedSecretKey types.String) (types.String, types.String, error) {
+	if pgpKey.IsNull() {
+		return secretKey, types.StringNull(), nil
+	}
+	if secretKey.IsNull() || secretKey.IsUnknown() {
+		return types.StringNull(), encryptedSecretKey, nil
+	}
+	if !encryptedSecretKey.IsNull() && !encryptedSecretKey.IsUnknown() {
+		return types.StringNull(), encryptedSecretKey, nil
+	}
+
+	encrypted, err := encryptSecret(pgpKey.ValueString(), secretKey.ValueString())
+	if err != nil {
+		return types.StringNull(), types.StringNull(), err
+	}
+	return types.StringNull(), types.StringValue(encrypted), nil
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// testPGPKey generates a key pair and returns it with the ASCII armored and the
+// base64 encoded binary public key.
+func testPGPKey(t *testing.T) (*openpgp.Entity, string, string) {
+	entity, err := openpgp.NewEntity("Test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatalf("could not generate pgp key: %s", err)
+	}
+
+	var binary bytes.Buffer
+	if err := entity.Serialize(&binary); err != nil {
+		t.Fatalf("could not serialize pgp key: %s", err)
+	}
+
+	var armored bytes.Buffer
+	w, err := armor.Encode(&armored, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("could not armor pgp key: %s", err)
+	}
+	if _, err := w.Write(binary.Bytes()); err != nil {
+		t.Fatalf("could not armor pgp key: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("could not armor pgp key: %s", err)
+	}
+	// armor does not end the block with a newline like exported keys do
+	armored.WriteString("\n")
+
+	return entity, armored.String(), base64.StdEncoding.EncodeToString(binary.Bytes())
+}
+
+// decryptSecret decrypts a secret encrypted by encryptSecret.
+func decryptSecret(entity *openpgp.Entity, encrypted string) (string, error) {
+	message, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+
+	md, err := openpgp.ReadMessage(bytes.NewReader(message), openpgp.EntityList{entity}, nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := io.ReadAll(md.UnverifiedBody)
+	return string(secret), err
+}
+
+func TestEncryptSecret(t *testing.T) {
+	entity, armored, encoded := testPGPKey(t)
+
+	for name, publicKey := range map[string]string{"armored": armored, "base64": encoded} {
+		t.Run(name, func(t *testing.T) {
+			encrypted, err := encryptSecret(publicKey, "secret")
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if strings.Contains(encrypted, "secret") {
+				t.Errorf("encrypted secret contains plain text")
+			}
+
+			secret, err := decryptSecret(entity, encrypted)
+			if err != nil {
+				t.Fatalf("could not decrypt secret: %s", err)
+			}
+			if secret != "secret" {
+				t.Errorf("expected decrypted secret 'secret', got '%s'", secret)
+			}
+		})
+	}
+}
+
+func TestReadPGPPublicKey_invalid(t *testing.T) {
+	for _, publicKey := range []string{"", "invalid", "-----BEGIN PGP PUBLIC KEY BLOCK-----\ninvalid\n-----END PGP PUBLIC KEY BLOCK-----"} {
+		if _, err := readPGPPublicKey(publicKey); err == nil {
+			t.Errorf("expected error for key %q", publicKey)
+		}
+	}
+}
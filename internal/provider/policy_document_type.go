@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ basetypes.StringTypable = PolicyDocumentType{}
+var _ basetypes.StringValuable = PolicyDocumentValue{}
+
+// PolicyDocumentType is a string type for IAM policy documents in JSON format.
+type PolicyDocumentType struct {
+	basetypes.StringType
+}
+
+func (t PolicyDocumentType) Equal(o attr.Type) bool {
+	_, ok := o.(PolicyDocumentType)
+	return ok
+}
+
+func (t PolicyDocumentType) String() string {
+	return "PolicyDocumentType"
+}
+
+func (t PolicyDocumentType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return PolicyDocumentValue{StringValue: in}, nil
+}
+
+func (t PolicyDocumentType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return PolicyDocumentValue{StringValue: stringValue}, nil
+}
+
+func (t PolicyDocumentType) ValueType(ctx context.Context) attr.Value {
+	return PolicyDocumentValue{}
+}
+
+// PolicyDocumentValue is an IAM policy document in JSON format.
+type PolicyDocumentValue struct {
+	basetypes.StringValue
+}
+
+func NewPolicyDocumentValue(value string) PolicyDocumentValue {
+	return PolicyDocumentValue{StringValue: basetypes.NewStringValue(value)}
+}
+
+func (v PolicyDocumentValue) Type(ctx context.Context) attr.Type {
+	return PolicyDocumentType{}
+}
+
+func (v PolicyDocumentValue) Equal(o attr.Value) bool {
+	other, ok := o.(PolicyDocumentValue)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// StringSemanticEquals returns true if both policy documents are equivalent,
+// ignoring formatting, key order, the order of values and whether single values
+// are given as a string or a list.
+func (v PolicyDocumentValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, d := newValuable.ToStringValue(ctx)
+	diags.Append(d...)
+	if diags.HasError() || v.IsNull() || v.IsUnknown() || newValue.IsNull() || newValue.IsUnknown() {
+		return false, diags
+	}
+
+	equal, err := policyDocumentsEquivalent(v.ValueString(), newValue.ValueString())
+	if err != nil {
+		// invalid documents are never equivalent, the validator reports details
+		return false, diags
+	}
+
+	return equal, diags
+}
+
+// policyDocumentsEquivalent compares two policy documents after normalization.
+func policyDocumentsEquivalent(a, b string) (bool, error) {
+	var docA, docB interface{}
+	if err := json.Unmarshal([]byte(a), &docA); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal([]byte(b), &docB); err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(normalizePolicy(docA, ""), normalizePolicy(docB, "")), nil
+}
+
+// policy keys whose values may either be a single value or a list of values.
+var policyListKeys = map[string]bool{
+	"statement":   true,
+	"action":      true,
+	"notaction":   true,
+	"resource":    true,
+	"notresource": true,
+}
+
+// normalizePolicy converts single values of list keys into lists and sorts
+// lists of strings. The values of principal types (`{"AWS": ...}`) and
+// condition keys (`{"StringEquals": {"aws:username": ...}}`) are lists as well.
+func normalizePolicy(v interface{}, key string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			childKey := strings.ToLower(k)
+			switch key {
+			case "principal", "notprincipal", "conditionoperator":
+				childKey = "list"
+			case "condition":
+				childKey = "conditionoperator"
+			}
+			res[k] = normalizePolicy(e, childKey)
+		}
+		if policyListKeys[key] {
+			// a single statement
+			return []interface{}{res}
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(t))
+		allStrings := true
+		for i, e := range t {
+			res[i] = normalizePolicy(e, "")
+			if _, ok := res[i].(string); !ok {
+				allStrings = false
+			}
+		}
+		if allStrings {
+			sort.Slice(res, func(i, j int) bool {
+				a, _ := res[i].(string)
+				b, _ := res[j].(string)
+				return a < b
+			})
+		}
+		return res
+	default:
+		if policyListKeys[key] || key == "list" {
+			return []interface{}{t}
+		}
+		return t
+	}
+}
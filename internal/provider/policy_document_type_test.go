@@ -0,0 +1,77 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestPolicyDocumentsEquivalent(t *testing.T) {
+	cases := map[string]struct {
+		a, b  string
+		equal bool
+	}{
+		"formatting": {
+			a:     `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["*"]}]}`,
+			b:     "{\n  \"Statement\": [{\"Resource\": [\"*\"], \"Effect\": \"Allow\", \"Action\": [\"s3:GetObject\"]}],\n  \"Version\": \"2012-10-17\"\n}",
+			equal: true,
+		},
+		"single values": {
+			a:     `{"Statement":{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam:::user/test"},"Action":"s3:GetObject","Resource":"*"}}`,
+			b:     `{"Statement":[{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam:::user/test"]},"Action":["s3:GetObject"],"Resource":["*"]}]}`,
+			equal: true,
+		},
+		"value order": {
+			a:     `{"Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:ListBucket"],"Resource":"*"}]}`,
+			b:     `{"Statement":[{"Effect":"Allow","Action":["s3:ListBucket","s3:GetObject"],"Resource":"*"}]}`,
+			equal: true,
+		},
+		"condition values": {
+			a:     `{"Statement":[{"Effect":"Allow","Action":"s3:*","Condition":{"StringEquals":{"aws:username":"test"}}}]}`,
+			b:     `{"Statement":[{"Effect":"Allow","Action":"s3:*","Condition":{"StringEquals":{"aws:username":["test"]}}}]}`,
+			equal: true,
+		},
+		"different actions": {
+			a:     `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			b:     `{"Statement":[{"Effect":"Allow","Action":"s3:PutObject","Resource":"*"}]}`,
+			equal: false,
+		},
+		"different effect": {
+			a:     `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			b:     `{"Statement":[{"Effect":"Deny","Action":"s3:GetObject","Resource":"*"}]}`,
+			equal: false,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			equal, err := policyDocumentsEquivalent(c.a, c.b)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if equal != c.equal {
+				t.Errorf("expected equal to be %t, got %t", c.equal, equal)
+			}
+		})
+	}
+}
+
+func TestIsKnownS3Action(t *testing.T) {
+	cases := map[string]bool{
+		"*":                  true,
+		"s3:*":               true,
+		"s3:GetObject":       true,
+		"s3:getobject":       true,
+		"s3:Get*":            true,
+		"s3:PutObject?cl":    true,
+		"s3:GetObjekt":       false,
+		"s3:Foo*":            false,
+		"iam:CreateUser":     false,
+		"GetObject":          false,
+		"s3:ListAllMyBucket": false,
+	}
+
+	for action, known := range cases {
+		if isKnownS3Action(action) != known {
+			t.Errorf("expected isKnownS3Action(%q) to be %t", action, known)
+		}
+	}
+}
@@ -2,15 +2,24 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -28,7 +37,28 @@ type RgwProvider struct {
 
 // RgwProviderModel describes the provider data model.
 type RgwProviderModel struct {
-	Endpoint  types.String `tfsdk:"endpoint"`
+	Endpoint           types.String                   `tfsdk:"endpoint"`
+	AdminEndpoint      types.String                   `tfsdk:"admin_endpoint"`
+	S3Endpoint         types.String                   `tfsdk:"s3_endpoint"`
+	WebsiteDomain      types.String                   `tfsdk:"website_domain"`
+	AccessKey          types.String                   `tfsdk:"access_key"`
+	SecretKey          types.String                   `tfsdk:"secret_key"`
+	S3Credentials      *RgwProviderS3CredentialsModel `tfsdk:"s3_credentials"`
+	Profile            types.String                   `tfsdk:"profile"`
+	SharedCredsFiles   []string                       `tfsdk:"shared_credentials_files"`
+	SharedConfigFiles  []string                       `tfsdk:"shared_config_files"`
+	CACertFile         types.String                   `tfsdk:"ca_cert_file"`
+	CACertPEM          types.String                   `tfsdk:"ca_cert_pem"`
+	ClientCert         types.String                   `tfsdk:"client_cert"`
+	ClientKey          types.String                   `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool                     `tfsdk:"insecure_skip_verify"`
+	MaxRetries         types.Int64                    `tfsdk:"max_retries"`
+	RetryMaxBackoff    types.String                   `tfsdk:"retry_max_backoff"`
+	RequestTimeout     types.String                   `tfsdk:"request_timeout"`
+}
+
+// RgwProviderS3CredentialsModel describes the credentials used for the S3 api.
+type RgwProviderS3CredentialsModel struct {
 	AccessKey types.String `tfsdk:"access_key"`
 	SecretKey types.String `tfsdk:"secret_key"`
 }
@@ -36,6 +66,10 @@ type RgwProviderModel struct {
 type RgwClient struct {
 	Admin *admin.API
 	S3    *s3.Client
+	SNS   *sns.Client
+
+	// domain of the s3website frontend, empty if not configured
+	WebsiteDomain string
 }
 
 func (p *RgwProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -47,18 +81,114 @@ func (p *RgwProvider) Schema(ctx context.Context, req provider.SchemaRequest, re
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				MarkdownDescription: "RGW Endpoint URL. Can be set via env 'TF_PROVIDER_RGW_ENDPOINT'",
-				Required:            true,
+				MarkdownDescription: "RGW Endpoint URL. Can be set via env 'TF_PROVIDER_RGW_ENDPOINT'. Required unless `admin_endpoint` and `s3_endpoint` are set.",
+				Optional:            true,
+			},
+			"admin_endpoint": schema.StringAttribute{
+				MarkdownDescription: "RGW admin api endpoint URL, if it differs from `endpoint`. Can be set via env 'TF_PROVIDER_RGW_ADMIN_ENDPOINT'",
+				Optional:            true,
+			},
+			"s3_endpoint": schema.StringAttribute{
+				MarkdownDescription: "RGW S3 endpoint URL, if it differs from `endpoint`. Can be set via env 'TF_PROVIDER_RGW_S3_ENDPOINT'",
+				Optional:            true,
+			},
+			"website_domain": schema.StringAttribute{
+				MarkdownDescription: "Domain of the s3website frontend of RGW (`rgw_dns_s3website_name`), e.g. `s3-website.example.com`. Used to compute the `website_endpoint` of buckets. Can be set via env 'TF_PROVIDER_RGW_WEBSITE_DOMAIN'",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?(:[0-9]+)?$`), "must be a domain name without scheme, e.g. `s3-website.example.com`"),
+				},
 			},
 			"access_key": schema.StringAttribute{
-				MarkdownDescription: "RGW Access Key. Should be set via env 'TF_PROVIDER_RGW_ACCESS_KEY'",
+				MarkdownDescription: "RGW Access Key. Should be set via env 'TF_PROVIDER_RGW_ACCESS_KEY'. Falls back to `profile` of the shared credentials and config files and env 'AWS_ACCESS_KEY_ID'.",
 				Optional:            true,
 			},
 			"secret_key": schema.StringAttribute{
-				MarkdownDescription: "RGW Secret Key. Should be set via env 'TF_PROVIDER_RGW_SECRET_KEY'",
+				MarkdownDescription: "RGW Secret Key. Should be set via env 'TF_PROVIDER_RGW_SECRET_KEY'. Falls back to `profile` of the shared credentials and config files and env 'AWS_SECRET_ACCESS_KEY'.",
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "Profile of the shared credentials and config files to read `access_key` and `secret_key` from, if they are not set. Can be set via env 'AWS_PROFILE', defaults to `default`.",
+				Optional:            true,
+			},
+			"shared_credentials_files": schema.ListAttribute{
+				MarkdownDescription: "Paths to credentials files in the AWS format. If multiple files contain the profile, the last one takes precedence. Defaults to env 'AWS_SHARED_CREDENTIALS_FILE' or `~/.aws/credentials`.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"shared_config_files": schema.ListAttribute{
+				MarkdownDescription: "Paths to config files in the AWS format, with profiles as `[profile <name>]`. If multiple files contain the profile, the last one takes precedence, the credentials files take precedence over the config files. Defaults to env 'AWS_CONFIG_FILE' or `~/.aws/config`.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file with PEM encoded CA certificates used to verify the RGW endpoint, in addition to the system CAs. Can be set via env 'TF_PROVIDER_RGW_CA_CERT_FILE'",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("ca_cert_pem")),
+				},
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded CA certificates used to verify the RGW endpoint, in addition to the system CAs.",
+				Optional:            true,
+			},
+			"client_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded client certificate for mutual TLS. Requires `client_key`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_key")),
+				},
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded private key of the client certificate. Requires `client_cert`.",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_cert")),
+				},
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Skip the verification of the TLS certificate of the RGW endpoint. Insecure, only use for testing.",
+				Optional:            true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of retries of requests failing with connection errors, throttling (`429`, `503 SlowDown`) or server errors. Requests which are not idempotent are only retried if RGW rejected them without processing. Defaults to `5`. Can be set via env 'TF_PROVIDER_RGW_MAX_RETRIES'",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_max_backoff": schema.StringAttribute{
+				MarkdownDescription: "Maximum wait time between retries, e.g. `30s`. The wait time grows exponentially with jitter up to this limit. Defaults to `20s`.",
+				Optional:            true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "Timeout of a single request to RGW including reading the response, e.g. `1m`. Defaults to `5m`.",
+				Optional:            true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"s3_credentials": schema.SingleNestedBlock{
+				MarkdownDescription: "Credentials used for the S3 api and the SNS api of topics, e.g. of a user without admin capabilities. Defaults to `access_key` and `secret_key`.",
+				Attributes: map[string]schema.Attribute{
+					"access_key": schema.StringAttribute{
+						MarkdownDescription: "S3 Access Key. Should be set via env 'TF_PROVIDER_RGW_S3_ACCESS_KEY'",
+						Optional:            true,
+					},
+					"secret_key": schema.StringAttribute{
+						MarkdownDescription: "S3 Secret Key. Should be set via env 'TF_PROVIDER_RGW_S3_SECRET_KEY'",
+						Optional:            true,
+						Sensitive:           true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -83,9 +213,125 @@ func (p *RgwProvider) Configure(ctx context.Context, req provider.ConfigureReque
 		data.SecretKey = types.StringValue(os.Getenv("TF_PROVIDER_RGW_SECRET_KEY"))
 	}
 
+	// Fall back to the shared credentials files and the env of the AWS SDK
+	if data.AccessKey.ValueString() == "" && data.SecretKey.ValueString() == "" {
+		value, ok, err := sharedCredentials(ctx, data.SharedCredsFiles, data.SharedConfigFiles, data.Profile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("could not read shared credentials", err.Error())
+			return
+		}
+
+		if ok {
+			data.AccessKey = types.StringValue(value.AccessKeyID)
+			data.SecretKey = types.StringValue(value.SecretAccessKey)
+		} else if !data.Profile.IsNull() || len(data.SharedCredsFiles) > 0 || len(data.SharedConfigFiles) > 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("profile"), "profile not found", fmt.Sprintf("profile %q not found in shared credentials and config files", data.Profile.ValueString()))
+			return
+		} else {
+			data.AccessKey = types.StringValue(os.Getenv("AWS_ACCESS_KEY_ID"))
+			data.SecretKey = types.StringValue(os.Getenv("AWS_SECRET_ACCESS_KEY"))
+		}
+	}
+
+	if data.AccessKey.ValueString() == "" || data.SecretKey.ValueString() == "" {
+		resp.Diagnostics.AddError("missing credentials", "access_key and secret_key must be set in the provider configuration, a shared credentials file or env")
+		return
+	}
+
+	if data.AdminEndpoint.IsNull() {
+		data.AdminEndpoint = types.StringValue(os.Getenv("TF_PROVIDER_RGW_ADMIN_ENDPOINT"))
+	}
+	if data.AdminEndpoint.ValueString() == "" {
+		data.AdminEndpoint = data.Endpoint
+	}
+
+	if data.S3Endpoint.IsNull() {
+		data.S3Endpoint = types.StringValue(os.Getenv("TF_PROVIDER_RGW_S3_ENDPOINT"))
+	}
+	if data.S3Endpoint.ValueString() == "" {
+		data.S3Endpoint = data.Endpoint
+	}
+
+	if data.AdminEndpoint.ValueString() == "" || data.S3Endpoint.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("endpoint"), "missing endpoint", "endpoint must be set in the provider configuration or via env 'TF_PROVIDER_RGW_ENDPOINT'")
+		return
+	}
+
+	if data.WebsiteDomain.IsNull() {
+		data.WebsiteDomain = types.StringValue(os.Getenv("TF_PROVIDER_RGW_WEBSITE_DOMAIN"))
+	}
+
+	// S3 credentials fall back to the admin credentials
+	s3Credentials := RgwProviderS3CredentialsModel{}
+	if data.S3Credentials != nil {
+		s3Credentials = *data.S3Credentials
+	}
+	if s3Credentials.AccessKey.IsNull() {
+		s3Credentials.AccessKey = types.StringValue(os.Getenv("TF_PROVIDER_RGW_S3_ACCESS_KEY"))
+	}
+	if s3Credentials.SecretKey.IsNull() {
+		s3Credentials.SecretKey = types.StringValue(os.Getenv("TF_PROVIDER_RGW_S3_SECRET_KEY"))
+	}
+	if s3Credentials.AccessKey.ValueString() == "" && s3Credentials.SecretKey.ValueString() == "" {
+		s3Credentials.AccessKey = data.AccessKey
+		s3Credentials.SecretKey = data.SecretKey
+	} else if s3Credentials.AccessKey.ValueString() == "" || s3Credentials.SecretKey.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("s3_credentials"), "incomplete s3 credentials", "both access_key and secret_key of the s3 credentials must be set")
+		return
+	}
+
+	if data.CACertFile.IsNull() {
+		if caCertFile := os.Getenv("TF_PROVIDER_RGW_CA_CERT_FILE"); caCertFile != "" {
+			data.CACertFile = types.StringValue(caCertFile)
+		}
+	}
+
+	if data.MaxRetries.IsNull() {
+		data.MaxRetries = types.Int64Value(defaultMaxRetries)
+		if maxRetries := os.Getenv("TF_PROVIDER_RGW_MAX_RETRIES"); maxRetries != "" {
+			value, err := strconv.ParseInt(maxRetries, 10, 64)
+			if err != nil || value < 0 {
+				resp.Diagnostics.AddAttributeError(path.Root("max_retries"), "invalid value of env 'TF_PROVIDER_RGW_MAX_RETRIES'", "value must be a non-negative integer")
+				return
+			}
+			data.MaxRetries = types.Int64Value(value)
+		}
+	}
+
+	// Create http client shared by the admin and s3 client
+	httpConfig := httpClientConfig{
+		CACertPEM:          []byte(data.CACertPEM.ValueString()),
+		ClientCertPEM:      []byte(data.ClientCert.ValueString()),
+		ClientKeyPEM:       []byte(data.ClientKey.ValueString()),
+		InsecureSkipVerify: data.InsecureSkipVerify.ValueBool(),
+		MaxRetries:         int(data.MaxRetries.ValueInt64()),
+		RetryMaxBackoff:    defaultRetryMaxBackoff,
+		RequestTimeout:     defaultRequestTimeout,
+	}
+	if !data.RetryMaxBackoff.IsNull() {
+		// already checked by the validator
+		httpConfig.RetryMaxBackoff, _ = time.ParseDuration(data.RetryMaxBackoff.ValueString())
+	}
+	if !data.RequestTimeout.IsNull() {
+		httpConfig.RequestTimeout, _ = time.ParseDuration(data.RequestTimeout.ValueString())
+	}
+	if !data.CACertFile.IsNull() {
+		caCert, err := os.ReadFile(data.CACertFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("ca_cert_file"), "could not read ca certificate file", err.Error())
+			return
+		}
+		httpConfig.CACertPEM = caCert
+	}
+	httpClient, err := newHTTPClient(httpConfig)
+	if err != nil {
+		resp.Diagnostics.AddError("could not configure tls", err.Error())
+		return
+	}
+
 	// Create Ceph RGW Admin Client
 	tflog.Debug(ctx, "Configuring Ceph RGW admin client")
-	admin, err := admin.New(data.Endpoint.ValueString(), data.AccessKey.ValueString(), data.SecretKey.ValueString(), nil)
+	admin, err := admin.New(data.AdminEndpoint.ValueString(), data.AccessKey.ValueString(), data.SecretKey.ValueString(), httpClient)
 	if err != nil {
 		resp.Diagnostics.AddError("could not create rgw admin client", err.Error())
 		return
@@ -96,17 +342,27 @@ func (p *RgwProvider) Configure(ctx context.Context, req provider.ConfigureReque
 	s3client := s3.New(s3.Options{
 		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
 			return aws.Credentials{
-				AccessKeyID:     data.AccessKey.ValueString(),
-				SecretAccessKey: data.SecretKey.ValueString(),
+				AccessKeyID:     s3Credentials.AccessKey.ValueString(),
+				SecretAccessKey: s3Credentials.SecretKey.ValueString(),
 			}, nil
 		}),
-		EndpointResolver: s3.EndpointResolverFromURL(data.Endpoint.ValueString()),
+		EndpointResolver: s3.EndpointResolverFromURL(data.S3Endpoint.ValueString()),
 		UsePathStyle:     true,
+		HTTPClient:       httpClient,
+		// retries are handled by the shared http client
+		Retryer: aws.NopRetryer{},
 	})
 
+	// Create sns client for bucket notification topics, served by the s3 endpoint
+	tflog.Debug(ctx, "Configuring SNS client from AWS SDK")
+	snsClient := newSNSClient(data.S3Endpoint.ValueString(), s3Credentials.AccessKey.ValueString(), s3Credentials.SecretKey.ValueString(), httpClient)
+
 	client := &RgwClient{
 		Admin: admin,
 		S3:    s3client,
+		SNS:   snsClient,
+
+		WebsiteDomain: data.WebsiteDomain.ValueString(),
 	}
 
 	resp.DataSourceData = client
@@ -118,11 +374,30 @@ func (p *RgwProvider) Resources(ctx context.Context) []func() resource.Resource
 		NewBucketResource,
 		NewUserResource,
 		NewBucketPolicyResource,
+		NewBucketLifecycleConfigurationResource,
+		NewBucketCorsConfigurationResource,
+		NewUserQuotaResource,
+		NewBucketQuotaResource,
+		NewUserKeyResource,
+		NewSubuserResource,
+		NewBucketLinkResource,
+		NewTopicResource,
+		NewBucketNotificationResource,
+		NewBucketServerSideEncryptionConfigurationResource,
+		NewBucketWebsiteConfigurationResource,
+		NewBucketAclResource,
+		NewBucketReplicationConfigurationResource,
+		NewS3ObjectResource,
 	}
 }
 
 func (p *RgwProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewUserDataSource,
+		NewUsersDataSource,
+		NewBucketDataSource,
+		NewBucketsDataSource,
+	}
 }
 
 func New(version string) func() provider.Provider {
@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// testAccProtoV6ProviderFactories are used to instantiate a provider during
+// acceptance testing. The factory function will be invoked for every Terraform
+// CLI command executed to create a provider server to which the CLI can
+// reattach.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"rgw": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+func TestAccProvider_separateEndpoints(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfigSeparateEndpoints() + testAccUserConfig + testAccBucketConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_user.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_bucket.test", "id", "test"),
+					testAccCheckUsedAccessKey(fake, "admin", "admin-key"),
+					testAccCheckUsedAccessKey(fake, "s3", "s3-key"),
+					testAccCheckNotUsedAccessKey(fake, "s3", "admin-key"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProvider_sharedCredentials(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	credentialsFile := filepath.Join(t.TempDir(), "credentials")
+	credentials := `[rgw]
+aws_access_key_id = file-key
+aws_secret_access_key = file-secret
+`
+	if err := os.WriteFile(credentialsFile, []byte(credentials), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "rgw" {
+  endpoint                 = %q
+  profile                  = "rgw"
+  shared_credentials_files = [%q]
+}
+`, fake.server.URL, credentialsFile) + testAccUserConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_user.test", "id", "test"),
+					testAccCheckUsedAccessKey(fake, "admin", "file-key"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProvider_sharedConfig(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	configFile := filepath.Join(t.TempDir(), "config")
+	config := `[profile rgw]
+aws_access_key_id = config-key
+aws_secret_access_key = config-secret
+`
+	if err := os.WriteFile(configFile, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "rgw" {
+  endpoint            = %q
+  profile             = "rgw"
+  shared_config_files = [%q]
+}
+`, fake.server.URL, configFile) + testAccUserConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_user.test", "id", "test"),
+					testAccCheckUsedAccessKey(fake, "admin", "config-key"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProvider_envCredentials(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	t.Setenv("TF_PROVIDER_RGW_ENDPOINT", fake.server.URL)
+	t.Setenv("TF_PROVIDER_RGW_ACCESS_KEY", "")
+	t.Setenv("TF_PROVIDER_RGW_SECRET_KEY", "")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "env-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_user.test", "id", "test"),
+					testAccCheckUsedAccessKey(fake, "admin", "env-key"),
+				),
+			},
+		},
+	})
+}
+
+// user used by the tests of user subresources.
+var testAccUserConfig = testAccNamedUserConfig("test", "", "Test User")
+
+// bucket used by the tests of bucket subresources.
+const testAccBucketConfig = `
+resource "rgw_bucket" "test" {
+  name = "test"
+}
+`
+
+// testAccNamedUserConfig returns the config of a user in the tenant, or without
+// tenant if it is empty. The resource is named like the user with "-" replaced
+// by "_".
+func testAccNamedUserConfig(username string, tenant string, displayName string) string {
+	config := fmt.Sprintf(`
+resource "rgw_user" %[1]q {
+  username     = %[2]q
+`, strings.ReplaceAll(username, "-", "_"), username)
+	if tenant != "" {
+		config += fmt.Sprintf("  tenant       = %q\n", tenant)
+	}
+	return config + fmt.Sprintf(`  display_name = %q
+}
+`, displayName)
+}
+
+func testAccCheckUsedAccessKey(fake *fakeRgw, api string, accessKey string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if !fake.usedAccessKey(api, accessKey) {
+			return fmt.Errorf("expected %s requests signed with access key %q", api, accessKey)
+		}
+		return nil
+	}
+}
+
+func testAccCheckNotUsedAccessKey(fake *fakeRgw, api string, accessKey string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if fake.usedAccessKey(api, accessKey) {
+			return fmt.Errorf("unexpected %s requests signed with access key %q", api, accessKey)
+		}
+		return nil
+	}
+}
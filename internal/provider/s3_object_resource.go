@@ -0,0 +1,583 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithConfigure = &S3ObjectResource{}
+var _ resource.ResourceWithImportState = &S3ObjectResource{}
+var _ resource.ResourceWithModifyPlan = &S3ObjectResource{}
+
+const (
+	// minimum size of parts of multipart uploads, except the last part
+	s3MinPartSize = 5 * 1024 * 1024
+
+	s3ObjectDefaultMultipartThreshold = 16 * 1024 * 1024
+	s3ObjectDefaultContentType        = "binary/octet-stream"
+)
+
+func NewS3ObjectResource() resource.Resource {
+	return &S3ObjectResource{}
+}
+
+type S3ObjectResource struct {
+	client *RgwClient
+}
+
+type S3ObjectResourceModel struct {
+	Id                 types.String      `tfsdk:"id"`
+	Bucket             types.String      `tfsdk:"bucket"`
+	Key                types.String      `tfsdk:"key"`
+	Content            types.String      `tfsdk:"content"`
+	ContentBase64      types.String      `tfsdk:"content_base64"`
+	Source             types.String      `tfsdk:"source"`
+	ContentType        types.String      `tfsdk:"content_type"`
+	CacheControl       types.String      `tfsdk:"cache_control"`
+	Metadata           map[string]string `tfsdk:"metadata"`
+	Tags               map[string]string `tfsdk:"tags"`
+	MultipartThreshold types.Int64       `tfsdk:"multipart_threshold"`
+	Etag               types.String      `tfsdk:"etag"`
+}
+
+func (r *S3ObjectResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_object"
+}
+
+func (r *S3ObjectResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Object in a bucket in Ceph RGW. Exactly one of `content`, `content_base64` or `source` must be set. Changes of the object in RGW are detected by its ETag, which does not work for objects encrypted with SSE-KMS.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Bucket and key of the object, `<bucket>/<key>`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"bucket": schema.StringAttribute{
+				MarkdownDescription: "Bucket Name",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "Key of the object",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 1024),
+				},
+			},
+			"content": schema.StringAttribute{
+				MarkdownDescription: "Content of the object as UTF-8 string",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("content_base64"), path.MatchRoot("source")),
+				},
+			},
+			"content_base64": schema.StringAttribute{
+				MarkdownDescription: "Content of the object as base64 encoded string, for binary content",
+				Optional:            true,
+			},
+			"source": schema.StringAttribute{
+				MarkdownDescription: "Path of a file uploaded as content of the object. Changes of the file are detected by its MD5 sum.",
+				Optional:            true,
+			},
+			"content_type": schema.StringAttribute{
+				MarkdownDescription: "MIME type of the object. Defaults to `" + s3ObjectDefaultContentType + "`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringDefaultModifier{s3ObjectDefaultContentType},
+				},
+			},
+			"cache_control": schema.StringAttribute{
+				MarkdownDescription: "Cache-Control header returned with the object",
+				Optional:            true,
+			},
+			"metadata": schema.MapAttribute{
+				MarkdownDescription: "User metadata of the object, sent as `x-amz-meta-*` headers. Keys must be lowercase.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z0-9_-]+$`), "must only contain lowercase letters, digits, '-' and '_'")),
+				},
+			},
+			"tags": schema.MapAttribute{
+				MarkdownDescription: "Tags of the object",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"multipart_threshold": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Objects larger than this size in bytes are uploaded in parts of this size. Defaults to `%d` (16 MiB), at least `%d` (5 MiB).", s3ObjectDefaultMultipartThreshold, s3MinPartSize),
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64DefaultModifier{s3ObjectDefaultMultipartThreshold},
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(s3MinPartSize),
+				},
+			},
+			"etag": schema.StringAttribute{
+				MarkdownDescription: "ETag of the object, the MD5 sum of the content, or of the MD5 sums of the parts for multipart uploads",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *S3ObjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to do on destroy, and the etag of new objects is only known
+	// after the upload
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state *S3ObjectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the etag of the content is compared with the etag read from RGW, so
+	// changes on both sides cause an upload
+	switch {
+	case plan.Content.IsUnknown() || plan.ContentBase64.IsUnknown() || plan.Source.IsUnknown() || plan.MultipartThreshold.IsUnknown():
+		plan.Etag = types.StringUnknown()
+	case !plan.Bucket.Equal(state.Bucket) || !plan.Key.Equal(state.Key):
+		// the object is replaced, Terraform plans the new object separately
+		plan.Etag = types.StringUnknown()
+	case !plan.Source.IsNull() && fileMissing(plan.Source.ValueString()):
+		// the file may be created during the apply
+		plan.Etag = types.StringUnknown()
+	default:
+		etag, err := objectContentEtag(plan)
+		if err != nil {
+			resp.Diagnostics.AddError("could not read object content", err.Error())
+			return
+		}
+		plan.Etag = types.StringValue(etag)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *S3ObjectResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *S3ObjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read Terraform plan data into the model
+	var data *S3ObjectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// PutObject or multipart upload
+	etag, err := r.upload(ctx, data)
+	if err != nil {
+		resp.Diagnostics.AddError("could not create object", err.Error())
+		return
+	}
+
+	data.Id = types.StringValue(fmt.Sprintf("%s/%s", data.Bucket.ValueString(), data.Key.ValueString()))
+	data.Etag = types.StringValue(etag)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *S3ObjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data *S3ObjectResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create HeadObject Request
+	s3req := &s3.HeadObjectInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+		Key:    aws.String(data.Key.ValueString()),
+	}
+
+	s3res, err := r.client.S3.HeadObject(ctx, s3req)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) {
+			switch ae.ErrorCode() {
+			case "NoSuchBucket", "NoSuchKey", "NotFound":
+				resp.State.RemoveResource(ctx)
+				return
+			}
+		}
+		resp.Diagnostics.AddError("could not get object", err.Error())
+		return
+	}
+
+	data.Etag = types.StringValue(strings.Trim(aws.StringValue(s3res.ETag), `"`))
+	data.ContentType = types.StringValue(aws.StringValue(s3res.ContentType))
+	data.CacheControl = stringOrNull(s3res.CacheControl)
+	switch {
+	case len(s3res.Metadata) > 0:
+		data.Metadata = s3res.Metadata
+	case data.Metadata != nil:
+		// keep configured empty metadata, RGW does not distinguish it from no metadata
+		data.Metadata = map[string]string{}
+	}
+	if data.MultipartThreshold.IsNull() {
+		data.MultipartThreshold = types.Int64Value(s3ObjectDefaultMultipartThreshold)
+	}
+
+	tagging, err := r.client.S3.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+		Key:    aws.String(data.Key.ValueString()),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("could not get object tags", err.Error())
+		return
+	}
+	switch {
+	case len(tagging.TagSet) > 0:
+		data.Tags = make(map[string]string, len(tagging.TagSet))
+		for _, tag := range tagging.TagSet {
+			data.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+	case data.Tags != nil:
+		// keep configured empty tags, RGW does not distinguish them from no tags
+		data.Tags = map[string]string{}
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *S3ObjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read Terraform plan data into the model
+	var data, state *S3ObjectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// only the tags changed, the object is not uploaded again
+	if data.Etag.Equal(state.Etag) && data.ContentType.Equal(state.ContentType) && data.CacheControl.Equal(state.CacheControl) &&
+		mapsEqual(data.Metadata, state.Metadata) {
+		_, err := r.client.S3.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
+			Bucket:  aws.String(data.Bucket.ValueString()),
+			Key:     aws.String(data.Key.ValueString()),
+			Tagging: &s3types.Tagging{TagSet: objectTagSet(data.Tags)},
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("could not modify object tags", err.Error())
+			return
+		}
+	} else {
+		etag, err := r.upload(ctx, data)
+		if err != nil {
+			resp.Diagnostics.AddError("could not modify object", err.Error())
+			return
+		}
+		data.Etag = types.StringValue(etag)
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *S3ObjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data *S3ObjectResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	s3req := &s3.DeleteObjectInput{
+		Bucket: aws.String(data.Bucket.ValueString()),
+		Key:    aws.String(data.Key.ValueString()),
+	}
+
+	_, err := r.client.S3.DeleteObject(ctx, s3req)
+	if err != nil {
+		var ae smithy.APIError
+		if errors.As(err, &ae) && ae.ErrorCode() == "NoSuchBucket" {
+			return
+		}
+		resp.Diagnostics.AddError("could not delete object", err.Error())
+		return
+	}
+}
+
+func (r *S3ObjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	bucket, key, ok := strings.Cut(req.ID, "/")
+	if !ok || bucket == "" || key == "" {
+		resp.Diagnostics.AddError("invalid import id", fmt.Sprintf("expected import id in the form 'bucket/key' or 'tenant:bucket/key', got '%s'", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), bucket)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key"), key)...)
+}
+
+// upload uploads the content of the object, with a multipart upload if it is
+// larger than the multipart threshold, and returns the ETag.
+func (r *S3ObjectResource) upload(ctx context.Context, data *S3ObjectResourceModel) (string, error) {
+	body, size, err := openObjectContent(data)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	var tagging *string
+	if len(data.Tags) > 0 {
+		tagging = aws.String(objectTagging(data.Tags))
+	}
+
+	partSize := data.MultipartThreshold.ValueInt64()
+	if size <= partSize {
+		s3res, err := r.client.S3.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:        aws.String(data.Bucket.ValueString()),
+			Key:           aws.String(data.Key.ValueString()),
+			Body:          body,
+			ContentLength: size,
+			ContentType:   aws.String(data.ContentType.ValueString()),
+			CacheControl:  stringPointer(data.CacheControl),
+			Metadata:      data.Metadata,
+			Tagging:       tagging,
+		})
+		if err != nil {
+			return "", err
+		}
+		return strings.Trim(aws.StringValue(s3res.ETag), `"`), nil
+	}
+
+	upload, err := r.client.S3.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:       aws.String(data.Bucket.ValueString()),
+		Key:          aws.String(data.Key.ValueString()),
+		ContentType:  aws.String(data.ContentType.ValueString()),
+		CacheControl: stringPointer(data.CacheControl),
+		Metadata:     data.Metadata,
+		Tagging:      tagging,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	parts, err := r.uploadParts(ctx, upload, body, partSize)
+	if err == nil {
+		var s3res *s3.CompleteMultipartUploadOutput
+		s3res, err = r.client.S3.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          upload.Bucket,
+			Key:             upload.Key,
+			UploadId:        upload.UploadId,
+			MultipartUpload: &s3types.CompletedMultipartUpload{Parts: parts},
+		})
+		if err == nil {
+			return strings.Trim(aws.StringValue(s3res.ETag), `"`), nil
+		}
+	}
+
+	// don't leave the uploaded parts behind, the upload error is more relevant
+	// than an error aborting the upload
+	_, _ = r.client.S3.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   upload.Bucket,
+		Key:      upload.Key,
+		UploadId: upload.UploadId,
+	})
+	return "", err
+}
+
+func (r *S3ObjectResource) uploadParts(ctx context.Context, upload *s3.CreateMultipartUploadOutput, body io.Reader, partSize int64) ([]s3types.CompletedPart, error) {
+	var parts []s3types.CompletedPart
+	buf := make([]byte, partSize)
+	for partNumber := int32(1); ; partNumber++ {
+		n, err := io.ReadFull(body, buf)
+		if n == 0 && err == io.EOF {
+			return parts, nil
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, err
+		}
+
+		s3res, uploadErr := r.client.S3.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:        upload.Bucket,
+			Key:           upload.Key,
+			UploadId:      upload.UploadId,
+			PartNumber:    partNumber,
+			Body:          bytes.NewReader(buf[:n]),
+			ContentLength: int64(n),
+		})
+		if uploadErr != nil {
+			return nil, uploadErr
+		}
+		parts = append(parts, s3types.CompletedPart{ETag: s3res.ETag, PartNumber: partNumber})
+
+		// the last part is smaller than the part size
+		if err == io.ErrUnexpectedEOF {
+			return parts, nil
+		}
+	}
+}
+
+type nopReadSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopReadSeekCloser) Close() error {
+	return nil
+}
+
+// openObjectContent returns a reader of the configured content of the object
+// and its size.
+func openObjectContent(data *S3ObjectResourceModel) (io.ReadSeekCloser, int64, error) {
+	switch {
+	case !data.Source.IsNull():
+		file, err := os.Open(data.Source.ValueString())
+		if err != nil {
+			return nil, 0, err
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, 0, err
+		}
+		return file, info.Size(), nil
+	case !data.ContentBase64.IsNull():
+		content, err := base64.StdEncoding.DecodeString(data.ContentBase64.ValueString())
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid content_base64: %w", err)
+		}
+		return nopReadSeekCloser{bytes.NewReader(content)}, int64(len(content)), nil
+	default:
+		content := []byte(data.Content.ValueString())
+		return nopReadSeekCloser{bytes.NewReader(content)}, int64(len(content)), nil
+	}
+}
+
+// fileMissing returns whether there is no file at the path. Other errors are
+// returned when the file is read.
+func fileMissing(name string) bool {
+	_, err := os.Stat(name)
+	return errors.Is(err, os.ErrNotExist)
+}
+
+// objectContentEtag returns the ETag RGW computes for the configured content.
+// Objects uploaded in parts have the MD5 sum of the MD5 sums of the parts with
+// the number of parts as suffix.
+func objectContentEtag(data *S3ObjectResourceModel) (string, error) {
+	body, size, err := openObjectContent(data)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	partSize := data.MultipartThreshold.ValueInt64()
+	if size <= partSize {
+		hash := md5.New()
+		if _, err := io.Copy(hash, body); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+
+	var sums []byte
+	parts := 0
+	for {
+		hash := md5.New()
+		n, err := io.CopyN(hash, body, partSize)
+		if n > 0 {
+			sums = append(sums, hash.Sum(nil)...)
+			parts++
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	sum := md5.Sum(sums)
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(sum[:]), parts), nil
+}
+
+// objectTagging returns the tags in the form of the x-amz-tagging header.
+func objectTagging(tags map[string]string) string {
+	values := url.Values{}
+	for k, v := range tags {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+func objectTagSet(tags map[string]string) []s3types.Tag {
+	tagSet := make([]s3types.Tag, 0, len(tags))
+	for k, v := range tags {
+		tagSet = append(tagSet, s3types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return tagSet
+}
+
+func mapsEqual(a map[string]string, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || v != w {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,257 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccS3ObjectResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if fake.object("test", "robots.txt") != nil {
+				return fmt.Errorf("object 'robots.txt' still exists")
+			}
+			return testAccCheckBucketDestroyed(fake, "test")(s)
+		},
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccS3ObjectResourceConfig(`content = "hello"`, "test"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_s3_object.test", "id", "test/robots.txt"),
+					resource.TestCheckResourceAttr("rgw_s3_object.test", "etag", "5d41402abc4b2a76b9719d911017c592"),
+					resource.TestCheckResourceAttr("rgw_s3_object.test", "content_type", "text/plain"),
+					resource.TestCheckResourceAttr("rgw_s3_object.test", "cache_control", "max-age=60"),
+					resource.TestCheckResourceAttr("rgw_s3_object.test", "metadata.owner", "infra"),
+					resource.TestCheckResourceAttr("rgw_s3_object.test", "tags.env", "test"),
+					testAccCheckS3Object(fake, "test", "robots.txt", "hello"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "rgw_s3_object.test",
+				ImportState:             true,
+				ImportStateId:           "test/robots.txt",
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"content"},
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + testAccS3ObjectResourceConfig(`content = "hello world"`, "test"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_s3_object.test", "etag", "5eb63bbbe01eeed093cb22bb8f5acdc3"),
+					testAccCheckS3Object(fake, "test", "robots.txt", "hello world"),
+				),
+			},
+			// Update testing, the same content as base64 and new tags don't upload the object again
+			{
+				PreConfig: func() {
+					fake.modifyFakeObject("test", "robots.txt", func(object *fakeObject) {
+						object.header.Set("X-Fake-Marker", "kept")
+					})
+				},
+				Config: fake.providerConfig() + testAccS3ObjectResourceConfig(`content_base64 = base64encode("hello world")`, "prod"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_s3_object.test", "etag", "5eb63bbbe01eeed093cb22bb8f5acdc3"),
+					resource.TestCheckResourceAttr("rgw_s3_object.test", "tags.env", "prod"),
+					testAccCheckS3Object(fake, "test", "robots.txt", "hello world"),
+					func(s *terraform.State) error {
+						if fake.object("test", "robots.txt").header.Get("X-Fake-Marker") != "kept" {
+							return fmt.Errorf("object 'robots.txt' was uploaded again")
+						}
+						return nil
+					},
+				),
+			},
+			// Drift testing, modified objects are uploaded again
+			{
+				PreConfig: func() {
+					fake.modifyFakeObject("test", "robots.txt", func(object *fakeObject) {
+						object.body = []byte("modified")
+						object.etag = "0123456789abcdef0123456789abcdef"
+					})
+				},
+				Config: fake.providerConfig() + testAccS3ObjectResourceConfig(`content_base64 = base64encode("hello world")`, "prod"),
+				Check:  testAccCheckS3Object(fake, "test", "robots.txt", "hello world"),
+			},
+		},
+	})
+}
+
+func TestAccS3ObjectResource_multipart(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	// two parts with the minimum part size
+	source := filepath.Join(t.TempDir(), "seed.bin")
+	content := bytes.Repeat([]byte("0123456789"), s3MinPartSize/10+1)
+	if err := os.WriteFile(source, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + testAccBucketConfig + fmt.Sprintf(`
+resource "rgw_s3_object" "test" {
+  bucket              = rgw_bucket.test.name
+  key                 = "seed.bin"
+  source              = %q
+  multipart_threshold = %d
+}
+`, source, s3MinPartSize),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("rgw_s3_object.test", "etag", regexp.MustCompile(`^[0-9a-f]{32}-2$`)),
+					resource.TestCheckResourceAttr("rgw_s3_object.test", "content_type", "binary/octet-stream"),
+					testAccCheckS3Object(fake, "test", "seed.bin", string(content)),
+				),
+			},
+			// changes of the source file are detected
+			{
+				PreConfig: func() {
+					if err := os.WriteFile(source, []byte("small"), 0o600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: fake.providerConfig() + testAccBucketConfig + fmt.Sprintf(`
+resource "rgw_s3_object" "test" {
+  bucket              = rgw_bucket.test.name
+  key                 = "seed.bin"
+  source              = %q
+  multipart_threshold = %d
+}
+`, source, s3MinPartSize),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("rgw_s3_object.test", "etag", regexp.MustCompile(`^[0-9a-f]{32}$`)),
+					testAccCheckS3Object(fake, "test", "seed.bin", "small"),
+				),
+			},
+			// a source file created during the apply does not fail the plan
+			{
+				Config: fake.providerConfig() + testAccBucketConfig + fmt.Sprintf(`
+resource "rgw_s3_object" "test" {
+  bucket              = rgw_bucket.test.name
+  key                 = "seed.bin"
+  source              = %q
+  multipart_threshold = %d
+}
+`, filepath.Join(filepath.Dir(source), "missing.bin"), s3MinPartSize),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccS3ObjectResource_multipartParts(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	// two parts of the threshold size and a last part of a single byte
+	partSize := s3MinPartSize + 1
+	source := filepath.Join(t.TempDir(), "seed.bin")
+	content := append(bytes.Repeat([]byte("a"), partSize), bytes.Repeat([]byte("b"), partSize)...)
+	content = append(content, 'c')
+	if err := os.WriteFile(source, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + testAccBucketConfig + fmt.Sprintf(`
+resource "rgw_s3_object" "test" {
+  bucket              = rgw_bucket.test.name
+  key                 = "seed.bin"
+  source              = %q
+  multipart_threshold = %d
+}
+`, source, partSize),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("rgw_s3_object.test", "etag", regexp.MustCompile(`^[0-9a-f]{32}-3$`)),
+					testAccCheckS3Object(fake, "test", "seed.bin", string(content)),
+					func(s *terraform.State) error {
+						if n := len(fake.bucket("test").uploads); n != 0 {
+							return fmt.Errorf("expected no pending multipart uploads, got %d", n)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccS3ObjectResource_emptyMaps(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckBucketDestroyed(fake, "test"),
+		Steps: []resource.TestStep{
+			// configured empty metadata and tags are kept
+			{
+				Config: fake.providerConfig() + testAccBucketConfig + `
+resource "rgw_s3_object" "test" {
+  bucket   = rgw_bucket.test.name
+  key      = "robots.txt"
+  content  = "hello"
+  metadata = {}
+  tags     = {}
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_s3_object.test", "metadata.%", "0"),
+					resource.TestCheckResourceAttr("rgw_s3_object.test", "tags.%", "0"),
+					testAccCheckS3Object(fake, "test", "robots.txt", "hello"),
+				),
+			},
+		},
+	})
+}
+
+func testAccS3ObjectResourceConfig(content string, env string) string {
+	return testAccBucketConfig + fmt.Sprintf(`
+resource "rgw_s3_object" "test" {
+  bucket        = rgw_bucket.test.name
+  key           = "robots.txt"
+  %s
+  content_type  = "text/plain"
+  cache_control = "max-age=60"
+
+  metadata = {
+    owner = "infra"
+  }
+
+  tags = {
+    env = %q
+  }
+}
+`, content, env)
+}
+
+// testAccCheckS3Object checks the content of an object in the fake.
+func testAccCheckS3Object(fake *fakeRgw, bucket string, key string, content string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		object := fake.object(bucket, key)
+		if object == nil {
+			return fmt.Errorf("object '%s' does not exist in bucket '%s'", key, bucket)
+		}
+		if string(object.body) != content {
+			return fmt.Errorf("expected content of object '%s' to be '%.20s', got '%.20s'", key, content, object.body)
+		}
+		return nil
+	}
+}
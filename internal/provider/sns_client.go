@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/smithy-go"
+)
+
+// region used to sign SNS requests, RGW does not check it
+const snsRegion = "default"
+
+// newSNSClient creates the client for the SNS compatible api of RGW, which is
+// served by the S3 endpoint and used to manage bucket notification topics.
+func newSNSClient(endpoint string, accessKey string, secretKey string, httpClient *http.Client) *sns.Client {
+	return sns.New(sns.Options{
+		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{
+				AccessKeyID:     accessKey,
+				SecretAccessKey: secretKey,
+			}, nil
+		}),
+		EndpointResolver: sns.EndpointResolverFromURL(endpoint),
+		Region:           snsRegion,
+		HTTPClient:       httpClient,
+		// retries are handled by the shared http client
+		Retryer: aws.NopRetryer{},
+	})
+}
+
+// isSNSNotFound checks whether a topic does not exist. RGW replies with S3
+// style errors, which can not always be parsed by the SNS client, so the
+// status code is checked as well.
+func isSNSNotFound(err error) bool {
+	var ae smithy.APIError
+	if errors.As(err, &ae) && ae.ErrorCode() == "NotFound" {
+		return true
+	}
+	var re *awshttp.ResponseError
+	return errors.As(err, &re) && re.HTTPStatusCode() == http.StatusNotFound
+}
@@ -0,0 +1,308 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// characters and length of swift secret keys generated by RGW
+const (
+	secretKeyBytes  = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	secretKeyLength = 40
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithConfigure = &SubuserResource{}
+var _ resource.ResourceWithImportState = &SubuserResource{}
+
+func NewSubuserResource() resource.Resource {
+	return &SubuserResource{}
+}
+
+type SubuserResource struct {
+	client *RgwClient
+}
+
+type SubuserResourceModel struct {
+	Id               types.String `tfsdk:"id"`
+	User             types.String `tfsdk:"user"`
+	Subuser          types.String `tfsdk:"subuser"`
+	Access           types.String `tfsdk:"access"`
+	GenerateSwiftKey types.Bool   `tfsdk:"generate_swift_key"`
+	SwiftSecretKey   types.String `tfsdk:"swift_secret_key"`
+}
+
+func (r *SubuserResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_subuser"
+}
+
+func (r *SubuserResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Subuser of a Ceph RGW User, e.g. for Swift access",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Full subuser ID (`user:subuser`), used as Swift user name",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user": schema.StringAttribute{
+				MarkdownDescription: "The user ID including the tenant (`tenant$user`), e.g. the `id` of a `rgw_user`.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"subuser": schema.StringAttribute{
+				MarkdownDescription: "Name of the subuser (without user ID)",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[^:]+$`), "must not be empty or contain ':'"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"access": schema.StringAttribute{
+				MarkdownDescription: "Access level of the subuser. One of `read`, `write`, `readwrite` or `full`.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(admin.SubuserAccessRead),
+						string(admin.SubuserAccessWrite),
+						string(admin.SubuserAccessReadWrite),
+						string(admin.SubuserAccessFull),
+					),
+				},
+			},
+			"generate_swift_key": schema.BoolAttribute{
+				MarkdownDescription: "Specify whether to generate a Swift secret key for the subuser.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolDefaultModifier{true},
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"swift_secret_key": schema.StringAttribute{
+				MarkdownDescription: "The generated Swift secret key",
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *SubuserResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *SubuserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read Terraform plan data into the model
+	var data *SubuserResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := fmt.Sprintf("%s:%s", data.User.ValueString(), data.Subuser.ValueString())
+
+	// create subuser
+	generate := data.GenerateSwiftKey.ValueBool()
+	subuser := admin.SubuserSpec{
+		Name:   id,
+		Access: admin.SubuserAccess(data.Access.ValueString()),
+	}
+	if generate {
+		// go-ceph does not pass generate-secret to the api, so the secret is
+		// generated here
+		secretKey, err := randomString(secretKeyBytes, secretKeyLength)
+		if err != nil {
+			resp.Diagnostics.AddError("could not generate swift secret key", err.Error())
+			return
+		}
+		keyType := "swift"
+		subuser.KeyType = &keyType
+		subuser.SecretKey = &secretKey
+	}
+	err := r.client.Admin.CreateSubuser(ctx, admin.User{ID: data.User.ValueString()}, subuser)
+	if err != nil {
+		resp.Diagnostics.AddError("could not create subuser", err.Error())
+		return
+	}
+
+	data.Id = types.StringValue(id)
+
+	// get generated swift key
+	user, err := r.client.Admin.GetUser(ctx, admin.User{ID: data.User.ValueString()})
+	if err != nil {
+		resp.Diagnostics.AddError("could not get user", err.Error())
+		return
+	}
+	data.SwiftSecretKey = swiftSecretKey(user, id)
+	if generate && data.SwiftSecretKey.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("swift_secret_key"), "api didn't return a swift key", fmt.Sprintf("expected a swift key for subuser '%s' in api response", id))
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SubuserResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data *SubuserResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// get user
+	user, err := r.client.Admin.GetUser(ctx, admin.User{ID: data.User.ValueString()})
+	if err != nil {
+		if errors.Is(err, admin.ErrNoSuchUser) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("could not get user", err.Error())
+		return
+	}
+
+	// find subuser
+	var subuser *admin.SubuserSpec
+	for i := range user.Subusers {
+		if user.Subusers[i].Name == data.Id.ValueString() {
+			subuser = &user.Subusers[i]
+			break
+		}
+	}
+	if subuser == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// update access, the api replies with different values than it accepts
+	switch subuser.Access {
+	case admin.SubuserAccessReplyReadWrite:
+		data.Access = types.StringValue(string(admin.SubuserAccessReadWrite))
+	case admin.SubuserAccessReplyFull:
+		data.Access = types.StringValue(string(admin.SubuserAccessFull))
+	case admin.SubuserAccessReplyNone:
+		data.Access = types.StringValue("")
+	default:
+		data.Access = types.StringValue(string(subuser.Access))
+	}
+
+	// update swift key, a missing key is generated by replacing the subuser
+	data.SwiftSecretKey = swiftSecretKey(user, data.Id.ValueString())
+	data.GenerateSwiftKey = types.BoolValue(!data.SwiftSecretKey.IsNull())
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SubuserResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read Terraform plan data into the model
+	var data *SubuserResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// modify subuser
+	err := r.client.Admin.ModifySubuser(ctx, admin.User{ID: data.User.ValueString()}, admin.SubuserSpec{
+		Name:   data.Id.ValueString(),
+		Access: admin.SubuserAccess(data.Access.ValueString()),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("could not modify subuser", err.Error())
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SubuserResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data *SubuserResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// remove subuser including its keys
+	purgeKeys := true
+	err := r.client.Admin.RemoveSubuser(ctx, admin.User{ID: data.User.ValueString()}, admin.SubuserSpec{
+		Name:      data.Id.ValueString(),
+		PurgeKeys: &purgeKeys,
+	})
+	if err != nil && !errors.Is(err, admin.ErrNoSuchUser) && !isAdminErrorCode(err, "NoSuchSubUser") {
+		resp.Diagnostics.AddError("could not remove subuser", err.Error())
+		return
+	}
+}
+
+func (r *SubuserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// accept "user:subuser", the user may contain a tenant ("tenant$user")
+	splittedId := strings.SplitN(req.ID, ":", 2)
+	if len(splittedId) != 2 || splittedId[0] == "" || splittedId[1] == "" {
+		resp.Diagnostics.AddError("invalid import id", fmt.Sprintf("expected import id in the form 'user:subuser' or 'tenant$user:subuser', got '%s'", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user"), splittedId[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("subuser"), splittedId[1])...)
+}
+
+// swiftSecretKey returns the swift secret key of a subuser or null.
+func swiftSecretKey(user admin.User, subuser string) types.String {
+	for _, k := range user.SwiftKeys {
+		if k.User == subuser {
+			return types.StringValue(k.SecretKey)
+		}
+	}
+	return types.StringNull()
+}
+
+// isAdminErrorCode checks the code of an error response of the admin api, for
+// codes go-ceph has no error constant for. go-ceph formats these errors as
+// "<code> <request id> <host id>".
+func isAdminErrorCode(err error, code string) bool {
+	return strings.HasPrefix(err.Error(), code+" ")
+}
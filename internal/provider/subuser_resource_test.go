@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccSubuserResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccSubuserResourceConfig("read"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_subuser.test", "id", "test:swift"),
+					resource.TestCheckResourceAttr("rgw_subuser.test", "access", "read"),
+					resource.TestCheckResourceAttr("rgw_subuser.test", "generate_swift_key", "true"),
+					resource.TestCheckResourceAttrSet("rgw_subuser.test", "swift_secret_key"),
+					testAccCheckSubuserAccess(fake, "test", "test:swift", "read"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_subuser.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing, access values differ in api replies
+			{
+				Config: fake.providerConfig() + testAccSubuserResourceConfig("readwrite"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_subuser.test", "access", "readwrite"),
+					testAccCheckSubuserAccess(fake, "test", "test:swift", string(admin.SubuserAccessReplyReadWrite)),
+				),
+			},
+			// Drift testing, subusers deleted outside of terraform are recreated
+			{
+				PreConfig: func() {
+					fake.modifyFakeUser("test", func(user *admin.User) {
+						user.Subusers = nil
+						user.SwiftKeys = nil
+					})
+				},
+				Config: fake.providerConfig() + testAccSubuserResourceConfig("readwrite"),
+				Check:  testAccCheckSubuserAccess(fake, "test", "test:swift", string(admin.SubuserAccessReplyReadWrite)),
+			},
+			// Delete testing, the user is kept
+			{
+				Config: fake.providerConfig() + testAccUserConfig,
+				Check: func(s *terraform.State) error {
+					if user := fake.user("test"); user == nil || len(user.Subusers) > 0 || len(user.SwiftKeys) > 0 {
+						return fmt.Errorf("expected user 'test' without subusers and swift keys")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestIsAdminErrorCode(t *testing.T) {
+	fake := newFakeRgw(t)
+	client, err := admin.New(fake.server.URL, "admin-key", "admin-secret", fake.server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.RemoveSubuser(context.Background(), admin.User{ID: fakeRgwS3User}, admin.SubuserSpec{Name: fakeRgwS3User + ":missing"})
+	if err == nil || !isAdminErrorCode(err, "NoSuchSubUser") {
+		t.Errorf("expected NoSuchSubUser error, got %v", err)
+	}
+	if isAdminErrorCode(err, "NoSuchSub") {
+		t.Errorf("expected only complete codes to match, got %v", err)
+	}
+}
+
+func testAccSubuserResourceConfig(access string) string {
+	return testAccUserConfig + fmt.Sprintf(`
+resource "rgw_subuser" "test" {
+  user    = rgw_user.test.id
+  subuser = "swift"
+  access  = %[1]q
+}
+`, access)
+}
+
+func testAccCheckSubuserAccess(fake *fakeRgw, uid string, subuser string, access string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		user := fake.user(uid)
+		if user == nil {
+			return fmt.Errorf("user '%s' does not exist", uid)
+		}
+		for _, su := range user.Subusers {
+			if su.Name == subuser {
+				if string(su.Access) != access {
+					return fmt.Errorf("expected access '%s' of subuser '%s', got '%s'", access, subuser, su.Access)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("user '%s' has no subuser '%s'", uid, subuser)
+	}
+}
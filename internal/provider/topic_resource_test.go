@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccTopicResource(t *testing.T) {
+	fake := newFakeRgw(t)
+	arn := fakeTopicArn("test")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if fake.topic(arn) != nil {
+				return fmt.Errorf("topic '%s' still exists", arn)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccTopicResourceConfig("http://example.com/notify", true),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_topic.test", "id", arn),
+					resource.TestCheckResourceAttr("rgw_topic.test", "arn", arn),
+					resource.TestCheckResourceAttr("rgw_topic.test", "persistent", "true"),
+					resource.TestCheckResourceAttr("rgw_topic.test", "opaque_data", "team=infra"),
+					testAccCheckTopicAttribute(fake, arn, "push-endpoint", "http://example.com/notify"),
+					testAccCheckTopicAttribute(fake, arn, "persistent", "true"),
+					testAccCheckTopicAttribute(fake, arn, "verify-ssl", "false"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "rgw_topic.test",
+				ImportState:             true,
+				ImportStateId:           arn,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"push_endpoint_attributes"},
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + testAccTopicResourceConfig("http://example.org/notify", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_topic.test", "id", arn),
+					resource.TestCheckResourceAttr("rgw_topic.test", "persistent", "false"),
+					testAccCheckTopicAttribute(fake, arn, "push-endpoint", "http://example.org/notify"),
+					testAccCheckTopicAttribute(fake, arn, "persistent", "false"),
+				),
+			},
+			// Drift testing, modified attributes are restored
+			{
+				PreConfig: func() {
+					fake.modifyFakeTopic(arn, func(topic *fakeTopic) {
+						topic.attributes["push-endpoint"] = "http://example.net/notify"
+						topic.attributes["OpaqueData"] = ""
+					})
+				},
+				Config: fake.providerConfig() + testAccTopicResourceConfig("http://example.org/notify", false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckTopicAttribute(fake, arn, "push-endpoint", "http://example.org/notify"),
+					testAccCheckTopicAttribute(fake, arn, "OpaqueData", "team=infra"),
+				),
+			},
+			// Drift testing, deleted topics are created again
+			{
+				PreConfig: func() {
+					fake.deleteTopic(arn)
+				},
+				Config: fake.providerConfig() + testAccTopicResourceConfig("http://example.org/notify", false),
+				Check:  testAccCheckTopicAttribute(fake, arn, "push-endpoint", "http://example.org/notify"),
+			},
+		},
+	})
+}
+
+func TestAccTopicResource_invalidAttributes(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + `
+resource "rgw_topic" "test" {
+  name = "test"
+
+  push_endpoint_attributes = {
+    push-endpoint = "http://example.com/notify"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`attribute 'push-endpoint' must be set by the dedicated attribute`),
+			},
+		},
+	})
+}
+
+func testAccTopicResourceConfig(endpoint string, persistent bool) string {
+	return fmt.Sprintf(`
+resource "rgw_topic" "test" {
+  name          = "test"
+  push_endpoint = %[1]q
+  persistent    = %[2]t
+  opaque_data   = "team=infra"
+
+  push_endpoint_attributes = {
+    verify-ssl = "false"
+  }
+}
+`, endpoint, persistent)
+}
+
+// testAccCheckTopicAttribute checks an attribute of a topic in the fake.
+func testAccCheckTopicAttribute(fake *fakeRgw, arn string, key string, value string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		topic := fake.topic(arn)
+		if topic == nil {
+			return fmt.Errorf("topic '%s' does not exist", arn)
+		}
+		if topic.attributes[key] != value {
+			return fmt.Errorf("expected attribute '%s' of topic '%s' to be '%s', got '%s'", key, arn, value, topic.attributes[key])
+		}
+		return nil
+	}
+}
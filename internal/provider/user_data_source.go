@@ -0,0 +1,173 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSourceWithConfigure = &UserDataSource{}
+
+func NewUserDataSource() datasource.DataSource {
+	return &UserDataSource{}
+}
+
+type UserDataSource struct {
+	client *RgwClient
+}
+
+type UserDataSourceModel struct {
+	Id          types.String   `tfsdk:"id"`
+	Username    types.String   `tfsdk:"username"`
+	Tenant      types.String   `tfsdk:"tenant"`
+	DisplayName types.String   `tfsdk:"display_name"`
+	Email       types.String   `tfsdk:"email"`
+	Caps        []UserCapModel `tfsdk:"caps"`
+	OpMask      types.String   `tfsdk:"op_mask"`
+	MaxBuckets  types.Int64    `tfsdk:"max_buckets"`
+	Suspended   types.Bool     `tfsdk:"suspended"`
+	AccessKeys  []string       `tfsdk:"access_keys"`
+	Principal   types.String   `tfsdk:"principal"`
+}
+
+func (d *UserDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user"
+}
+
+func (d *UserDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Ceph RGW User",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The user ID including the tenant (`tenant$user`)",
+				Computed:            true,
+			},
+			"username": schema.StringAttribute{
+				MarkdownDescription: "The user ID (without tenant).",
+				Required:            true,
+			},
+			"tenant": schema.StringAttribute{
+				MarkdownDescription: "The tenant under which a user is a part of.",
+				Optional:            true,
+			},
+			"display_name": schema.StringAttribute{
+				MarkdownDescription: "Display Name of user",
+				Computed:            true,
+			},
+			"email": schema.StringAttribute{
+				MarkdownDescription: "The email address associated with the user.",
+				Computed:            true,
+			},
+			"caps": schema.ListNestedAttribute{
+				MarkdownDescription: "Admin capabilities of the user",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Computed: true,
+						},
+						"perm": schema.StringAttribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+			"op_mask": schema.StringAttribute{
+				MarkdownDescription: "The op-mask of the user",
+				Computed:            true,
+			},
+			"max_buckets": schema.Int64Attribute{
+				MarkdownDescription: "The maximum number of buckets the user can own.",
+				Computed:            true,
+			},
+			"suspended": schema.BoolAttribute{
+				MarkdownDescription: "Whether the user is suspended.",
+				Computed:            true,
+			},
+			"access_keys": schema.ListAttribute{
+				MarkdownDescription: "Access keys of the s3 key pairs of the user",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"principal": schema.StringAttribute{
+				MarkdownDescription: "Computed principal to be used in policies",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *UserDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *UserDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Read Terraform configuration data into the model
+	var data *UserDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := data.Username.ValueString()
+	if !data.Tenant.IsNull() {
+		id = fmt.Sprintf("%s$%s", data.Tenant.ValueString(), data.Username.ValueString())
+	}
+
+	// get user
+	user, err := d.client.Admin.GetUser(ctx, admin.User{ID: id})
+	if err != nil {
+		resp.Diagnostics.AddError("could not get user", err.Error())
+		return
+	}
+
+	data.Id = types.StringValue(user.ID)
+	data.Principal = types.StringValue(fmt.Sprintf("arn:aws:iam::%s:user/%s", data.Tenant.ValueString(), data.Username.ValueString()))
+	data.DisplayName = types.StringValue(user.DisplayName)
+	data.Email = types.StringValue(user.Email)
+	data.OpMask = types.StringValue(user.OpMask)
+
+	data.Caps = make([]UserCapModel, len(user.Caps))
+	for i, c := range user.Caps {
+		data.Caps[i].Type = types.StringValue(c.Type)
+		data.Caps[i].Perm = types.StringValue(c.Perm)
+	}
+
+	data.MaxBuckets = types.Int64Null()
+	if user.MaxBuckets != nil {
+		data.MaxBuckets = types.Int64Value(int64(*user.MaxBuckets))
+	}
+
+	data.Suspended = types.BoolValue(user.Suspended != nil && *user.Suspended > 0)
+
+	// only expose access keys, never secrets
+	data.AccessKeys = make([]string, 0, len(user.Keys))
+	for _, k := range user.Keys {
+		data.AccessKeys = append(data.AccessKeys, k.AccessKey)
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
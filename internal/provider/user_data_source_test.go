@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccUserDataSource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fake.providerConfig() + testAccUserDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.rgw_user.test", "id", "tenant$test"),
+					resource.TestCheckResourceAttr("data.rgw_user.test", "display_name", "Test User"),
+					resource.TestCheckResourceAttr("data.rgw_user.test", "principal", "arn:aws:iam::tenant:user/test"),
+					resource.TestCheckResourceAttr("data.rgw_user.test", "access_keys.#", "1"),
+					resource.TestCheckResourceAttrPair("data.rgw_user.test", "access_keys.0", "rgw_user.test", "access_key"),
+				),
+			},
+		},
+	})
+}
+
+var testAccUserDataSourceConfig = testAccNamedUserConfig("test", "tenant", "Test User") + `
+data "rgw_user" "test" {
+  username = rgw_user.test.username
+  tenant   = rgw_user.test.tenant
+}
+`
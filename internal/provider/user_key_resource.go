@@ -0,0 +1,281 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithConfigure = &UserKeyResource{}
+var _ resource.ResourceWithImportState = &UserKeyResource{}
+
+func NewUserKeyResource() resource.Resource {
+	return &UserKeyResource{}
+}
+
+type UserKeyResource struct {
+	client *RgwClient
+}
+
+type UserKeyResourceModel struct {
+	Id                 types.String      `tfsdk:"id"`
+	User               types.String      `tfsdk:"user"`
+	AccessKey          types.String      `tfsdk:"access_key"`
+	SecretKey          types.String      `tfsdk:"secret_key"`
+	PGPKey             types.String      `tfsdk:"pgp_key"`
+	EncryptedSecretKey types.String      `tfsdk:"encrypted_secret_key"`
+	Keepers            map[string]string `tfsdk:"keepers"`
+}
+
+func (r *UserKeyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_key"
+}
+
+func (r *UserKeyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Additional S3 key pair of a Ceph RGW User. Use `keepers` together with `create_before_destroy` to rotate keys without downtime. Set `exclusive_s3_credentials` of the `rgw_user` to `false`, otherwise the key is deleted again.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user": schema.StringAttribute{
+				MarkdownDescription: "The user ID including the tenant (`tenant$user`), e.g. the `id` of a `rgw_user`.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"access_key": schema.StringAttribute{
+				MarkdownDescription: "The access key. Generated if not set.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"secret_key": schema.StringAttribute{
+				MarkdownDescription: "The generated secret key. Not set if `pgp_key` is set.",
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"pgp_key": schema.StringAttribute{
+				MarkdownDescription: "ASCII armored or base64 encoded PGP public key. If set, the secret key is only saved encrypted in `encrypted_secret_key` instead of `secret_key`.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					pgpKeyValidator{},
+				},
+			},
+			"encrypted_secret_key": schema.StringAttribute{
+				MarkdownDescription: "The generated secret key encrypted with `pgp_key` and base64 encoded, decrypt it with `base64 --decode | gpg --decrypt`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"keepers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary map of values that, when changed, will trigger the creation of a new key pair.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *UserKeyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *UserKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read Terraform plan data into the model
+	var data *UserKeyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// generate access key, so the created key can be found in the api response
+	if data.AccessKey.IsUnknown() || data.AccessKey.IsNull() {
+		accessKey, err := randomString(accessKeyBytes, accessKeyLength)
+		if err != nil {
+			resp.Diagnostics.AddError("could not generate access key", err.Error())
+			return
+		}
+		data.AccessKey = types.StringValue(accessKey)
+	}
+
+	// create key
+	generate := true
+	keys, err := r.client.Admin.CreateKey(ctx, admin.UserKeySpec{
+		UID:         data.User.ValueString(),
+		KeyType:     "s3",
+		GenerateKey: &generate,
+		AccessKey:   data.AccessKey.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("could not create s3 key", err.Error())
+		return
+	}
+
+	data.SecretKey = types.StringUnknown()
+	if keys != nil {
+		for _, k := range *keys {
+			if k.AccessKey == data.AccessKey.ValueString() {
+				data.SecretKey = types.StringValue(k.SecretKey)
+				break
+			}
+		}
+	}
+	if data.SecretKey.IsUnknown() {
+		resp.Diagnostics.AddError("could not find expected s3 credentials in api response", fmt.Sprintf("none of the s3 key pairs returned by the api matched the access key '%s'", data.AccessKey.ValueString()))
+		return
+	}
+
+	data.Id = types.StringValue(data.AccessKey.ValueString())
+
+	// encrypt secret key
+	data.SecretKey, data.EncryptedSecretKey, err = protectSecretKey(data.PGPKey, data.SecretKey, types.StringNull())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("pgp_key"), "could not encrypt secret key", err.Error())
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data *UserKeyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// get user
+	user, err := r.client.Admin.GetUser(ctx, admin.User{
+		ID: data.User.ValueString(),
+	})
+	if err != nil {
+		if errors.Is(err, admin.ErrNoSuchUser) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("could not get user", err.Error())
+		return
+	}
+
+	// find key
+	found := false
+	for _, k := range user.Keys {
+		if k.AccessKey == data.AccessKey.ValueString() {
+			found = true
+			data.SecretKey = types.StringValue(k.SecretKey)
+			break
+		}
+	}
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// encrypt secret key
+	data.SecretKey, data.EncryptedSecretKey, err = protectSecretKey(data.PGPKey, data.SecretKey, data.EncryptedSecretKey)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("pgp_key"), "could not encrypt secret key", err.Error())
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read Terraform plan data into the model
+	var data *UserKeyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// All attributes require replacement, there is nothing to update in place
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data *UserKeyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// remove key
+	err := r.client.Admin.RemoveKey(ctx, admin.UserKeySpec{
+		UID:       data.User.ValueString(),
+		KeyType:   "s3",
+		AccessKey: data.AccessKey.ValueString(),
+	})
+	if err != nil && !errors.Is(err, admin.ErrNoSuchUser) && !errors.Is(err, admin.ErrInvalidAccessKey) {
+		resp.Diagnostics.AddError("could not remove s3 key", err.Error())
+		return
+	}
+}
+
+func (r *UserKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// accept "user/access_key", the user may contain a tenant ("tenant$user")
+	i := strings.LastIndex(req.ID, "/")
+	if i < 1 || i == len(req.ID)-1 {
+		resp.Diagnostics.AddError("invalid import id", fmt.Sprintf("expected import id in the form 'user/access_key' or 'tenant$user/access_key', got '%s'", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID[i+1:])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user"), req.ID[:i])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("access_key"), req.ID[i+1:])...)
+}
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccUserKeyResource(t *testing.T) {
+	fake := newFakeRgw(t)
+	var accessKey string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccUserKeyResourceConfig("1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_user_key.test", "user", "test"),
+					resource.TestCheckResourceAttrSet("rgw_user_key.test", "access_key"),
+					resource.TestCheckResourceAttrSet("rgw_user_key.test", "secret_key"),
+					resource.TestCheckResourceAttrPair("rgw_user_key.test", "id", "rgw_user_key.test", "access_key"),
+					testAccCheckUserKeyExists(fake, "test", "rgw_user_key.test"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_user_key.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["rgw_user_key.test"]
+					if !ok {
+						return "", fmt.Errorf("resource rgw_user_key.test not found")
+					}
+					return fmt.Sprintf("test/%s", rs.Primary.Attributes["access_key"]), nil
+				},
+				ImportStateVerifyIgnore: []string{"keepers"},
+			},
+			// Update testing, changed keepers rotate the key
+			{
+				Config: fake.providerConfig() + testAccUserKeyResourceConfig("2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckUserKeyExists(fake, "test", "rgw_user_key.test"),
+					func(s *terraform.State) error {
+						accessKey = s.RootModule().Resources["rgw_user_key.test"].Primary.Attributes["access_key"]
+						return nil
+					},
+				),
+			},
+			// Drift testing, keys deleted outside of terraform are recreated
+			{
+				PreConfig: func() {
+					fake.modifyFakeUser("test", func(user *admin.User) {
+						keys := user.Keys[:0]
+						for _, k := range user.Keys {
+							if k.AccessKey != accessKey {
+								keys = append(keys, k)
+							}
+						}
+						user.Keys = keys
+					})
+				},
+				Config: fake.providerConfig() + testAccUserKeyResourceConfig("2"),
+				Check:  testAccCheckUserKeyExists(fake, "test", "rgw_user_key.test"),
+			},
+		},
+	})
+}
+
+func TestAccUserKeyResource_pgpKey(t *testing.T) {
+	fake := newFakeRgw(t)
+	entity, publicKey, _ := testPGPKey(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fake.providerConfig() + fmt.Sprintf(`
+resource "rgw_user" "test" {
+  username                 = "test"
+  display_name             = "Test User"
+  exclusive_s3_credentials = false
+}
+
+resource "rgw_user_key" "test" {
+  user    = rgw_user.test.id
+  pgp_key = <<EOT
+%sEOT
+}
+`, publicKey),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("rgw_user_key.test", "secret_key"),
+					testAccCheckEncryptedSecretKey(fake, entity, "test", "rgw_user_key.test"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserKeyResourceConfig(rotation string) string {
+	return fmt.Sprintf(`
+resource "rgw_user" "test" {
+  username                 = "test"
+  display_name             = "Test User"
+  exclusive_s3_credentials = false
+}
+
+resource "rgw_user_key" "test" {
+  user = rgw_user.test.id
+
+  keepers = {
+    rotation = %[1]q
+  }
+}
+`, rotation)
+}
+
+// testAccCheckUserKeyExists checks that the access key of a resource exists in the fake.
+func testAccCheckUserKeyExists(fake *fakeRgw, uid string, resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+		accessKey := rs.Primary.Attributes["access_key"]
+
+		user := fake.user(uid)
+		if user == nil {
+			return fmt.Errorf("user '%s' does not exist", uid)
+		}
+		for _, k := range user.Keys {
+			if k.AccessKey == accessKey {
+				return nil
+			}
+		}
+		return fmt.Errorf("user '%s' has no access key '%s'", uid, accessKey)
+	}
+}
+
+// testAccCheckEncryptedSecretKey decrypts the encrypted secret key of a resource
+// and compares it with the secret key of the access key in the fake.
+func testAccCheckEncryptedSecretKey(fake *fakeRgw, entity *openpgp.Entity, uid string, resourceName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource %s not found", resourceName)
+		}
+
+		secretKey, err := decryptSecret(entity, rs.Primary.Attributes["encrypted_secret_key"])
+		if err != nil {
+			return fmt.Errorf("could not decrypt secret key: %w", err)
+		}
+
+		user := fake.user(uid)
+		if user == nil {
+			return fmt.Errorf("user '%s' does not exist", uid)
+		}
+		for _, k := range user.Keys {
+			if k.AccessKey == rs.Primary.Attributes["access_key"] {
+				if k.SecretKey != secretKey {
+					return fmt.Errorf("decrypted secret key does not match the secret key of '%s'", k.AccessKey)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("user '%s' has no access key '%s'", uid, rs.Primary.Attributes["access_key"])
+	}
+}
@@ -0,0 +1,259 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// quota value representing no limit.
+const quotaUnlimited int64 = -1
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.ResourceWithConfigure = &UserQuotaResource{}
+var _ resource.ResourceWithImportState = &UserQuotaResource{}
+var _ resource.ResourceWithModifyPlan = &UserQuotaResource{}
+
+func NewUserQuotaResource() resource.Resource {
+	return &UserQuotaResource{}
+}
+
+type UserQuotaResource struct {
+	client *RgwClient
+}
+
+type UserQuotaResourceModel struct {
+	Id         types.String `tfsdk:"id"`
+	User       types.String `tfsdk:"user"`
+	Enabled    types.Bool   `tfsdk:"enabled"`
+	MaxSize    types.Int64  `tfsdk:"max_size"`
+	MaxObjects types.Int64  `tfsdk:"max_objects"`
+}
+
+func (r *UserQuotaResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_user_quota"
+}
+
+func (r *UserQuotaResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Quota of a Ceph RGW User. The quota is disabled and reset to unlimited on deletion.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user": schema.StringAttribute{
+				MarkdownDescription: "The user ID including the tenant (`tenant$user`), e.g. the `id` of a `rgw_user`.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Specify whether the quota is enforced.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolDefaultModifier{true},
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"max_size": schema.Int64Attribute{
+				MarkdownDescription: "Maximum size of all objects of the user in bytes. `-1` means unlimited.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64DefaultModifier{quotaUnlimited},
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(quotaUnlimited),
+				},
+			},
+			"max_objects": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of objects of the user. `-1` means unlimited.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64DefaultModifier{quotaUnlimited},
+					int64planmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.Int64{
+					int64validator.AtLeast(quotaUnlimited),
+				},
+			},
+		},
+	}
+}
+
+func (r *UserQuotaResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config, plan *UserQuotaResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// values which are not configured are reset to their defaults, so that
+	// quotas changed outside of terraform are restored
+	if config.Enabled.IsNull() {
+		plan.Enabled = types.BoolValue(true)
+	}
+	if config.MaxSize.IsNull() {
+		plan.MaxSize = types.Int64Value(quotaUnlimited)
+	}
+	if config.MaxObjects.IsNull() {
+		plan.MaxObjects = types.Int64Value(quotaUnlimited)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *UserQuotaResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *UserQuotaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	// Read Terraform plan data into the model
+	var data *UserQuotaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// set quota
+	err := r.client.Admin.SetUserQuota(ctx, quotaSpec(data.User.ValueString(), data.Enabled.ValueBool(), data.MaxSize.ValueInt64(), data.MaxObjects.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError("could not set user quota", err.Error())
+		return
+	}
+
+	// use user id as resource id
+	data.Id = types.StringValue(data.User.ValueString())
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserQuotaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Read Terraform prior state data into the model
+	var data *UserQuotaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// get quota
+	quota, err := r.client.Admin.GetUserQuota(ctx, admin.QuotaSpec{
+		UID: data.User.ValueString(),
+	})
+	if err != nil {
+		if errors.Is(err, admin.ErrNoSuchUser) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("could not get user quota", err.Error())
+		return
+	}
+
+	data.Enabled = types.BoolValue(quota.Enabled != nil && *quota.Enabled)
+	data.MaxSize = types.Int64Value(quotaValue(quota.MaxSize))
+	data.MaxObjects = types.Int64Value(quotaValue(quota.MaxObjects))
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserQuotaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Read Terraform plan data into the model
+	var data *UserQuotaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// set quota
+	err := r.client.Admin.SetUserQuota(ctx, quotaSpec(data.User.ValueString(), data.Enabled.ValueBool(), data.MaxSize.ValueInt64(), data.MaxObjects.ValueInt64()))
+	if err != nil {
+		resp.Diagnostics.AddError("could not modify user quota", err.Error())
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UserQuotaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// Read Terraform prior state data into the model
+	var data *UserQuotaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// reset quota
+	err := r.client.Admin.SetUserQuota(ctx, quotaSpec(data.User.ValueString(), false, quotaUnlimited, quotaUnlimited))
+	if err != nil && !errors.Is(err, admin.ErrNoSuchUser) {
+		resp.Diagnostics.AddError("could not reset user quota", err.Error())
+		return
+	}
+}
+
+func (r *UserQuotaResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("user"), req.ID)...)
+}
+
+func quotaSpec(uid string, enabled bool, maxSize int64, maxObjects int64) admin.QuotaSpec {
+	return admin.QuotaSpec{
+		UID:        uid,
+		Enabled:    &enabled,
+		MaxSize:    &maxSize,
+		MaxObjects: &maxObjects,
+	}
+}
+
+// quotaValue normalizes quota values returned by the api, all negative values
+// mean unlimited.
+func quotaValue(v *int64) int64 {
+	if v == nil || *v < 0 {
+		return quotaUnlimited
+	}
+	return *v
+}
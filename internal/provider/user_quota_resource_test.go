@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccUserQuotaResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccUserQuotaResourceConfig(1024),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_user_quota.test", "id", "test"),
+					resource.TestCheckResourceAttr("rgw_user_quota.test", "enabled", "true"),
+					resource.TestCheckResourceAttr("rgw_user_quota.test", "max_size", "1024"),
+					resource.TestCheckResourceAttr("rgw_user_quota.test", "max_objects", "-1"),
+					testAccCheckUserQuota(fake, "test", true, 1024),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_user_quota.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + testAccUserQuotaResourceConfig(2048),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_user_quota.test", "max_size", "2048"),
+					testAccCheckUserQuota(fake, "test", true, 2048),
+				),
+			},
+			// Drift testing, quotas changed outside of terraform are restored
+			{
+				PreConfig: func() {
+					fake.modifyFakeUser("test", func(user *admin.User) {
+						enabled := false
+						user.UserQuota.Enabled = &enabled
+					})
+				},
+				Config: fake.providerConfig() + testAccUserQuotaResourceConfig(2048),
+				Check:  testAccCheckUserQuota(fake, "test", true, 2048),
+			},
+			// Delete testing, the quota is reset
+			{
+				Config: fake.providerConfig() + testAccUserConfig,
+				Check:  testAccCheckUserQuota(fake, "test", false, quotaUnlimited),
+			},
+		},
+	})
+}
+
+func testAccUserQuotaResourceConfig(maxSize int) string {
+	return testAccUserConfig + fmt.Sprintf(`
+resource "rgw_user_quota" "test" {
+  user     = rgw_user.test.id
+  max_size = %[1]d
+}
+`, maxSize)
+}
+
+func testAccCheckUserQuota(fake *fakeRgw, uid string, enabled bool, maxSize int64) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		user := fake.user(uid)
+		if user == nil {
+			return fmt.Errorf("user '%s' does not exist", uid)
+		}
+		return checkFakeQuota(user.UserQuota, enabled, maxSize)
+	}
+}
+
+func checkFakeQuota(quota admin.QuotaSpec, enabled bool, maxSize int64) error {
+	if quota.Enabled == nil || *quota.Enabled != enabled {
+		return fmt.Errorf("expected quota enabled to be %t", enabled)
+	}
+	if quota.MaxSize == nil || *quota.MaxSize != maxSize {
+		return fmt.Errorf("expected quota max size to be %d", maxSize)
+	}
+	return nil
+}
@@ -2,9 +2,10 @@ package provider
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
-	"math/rand"
+	"math/big"
 	"strings"
 
 	"github.com/ceph/go-ceph/rgw/admin"
@@ -21,10 +22,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-const accessKeyBytes = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+// characters and length of access keys generated by RGW
+const (
+	accessKeyBytes  = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	accessKeyLength = 20
+)
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.ResourceWithConfigure = &UserResource{}
+var _ resource.ResourceWithImportState = &UserResource{}
 
 func NewUserResource() resource.Resource {
 	return &UserResource{}
@@ -48,6 +54,8 @@ type UserResourceModel struct {
 	Tenant                 types.String   `tfsdk:"tenant"`
 	AccessKey              types.String   `tfsdk:"access_key"`
 	SecretKey              types.String   `tfsdk:"secret_key"`
+	PGPKey                 types.String   `tfsdk:"pgp_key"`
+	EncryptedSecretKey     types.String   `tfsdk:"encrypted_secret_key"`
 	PurgeDataOnDelete      types.Bool     `tfsdk:"purge_data_on_delete"`
 	Principal              types.String   `tfsdk:"principal"`
 }
@@ -98,18 +106,30 @@ func (r *UserResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 			},
 			"exclusive_s3_credentials": schema.BoolAttribute{
 				Description:         "Specify whether other s3 credentials for this user not managed by this ressource should be deleted.",
-				MarkdownDescription: "Specify how to deal with s3 credentials for this user not managed by this resource. Set to `true` to delete all other s3 credentials. Set to `false` to ignore other credentials.",
+				MarkdownDescription: "Specify how to deal with s3 credentials for this user not managed by this resource. Set to `true` to delete all other s3 credentials. Set to `false` to ignore other credentials. Must be `false` if additional keys are managed via `rgw_user_key`.",
 				Optional:            true,
 			},
-			"caps": schema.ListNestedAttribute{
-				Optional: true,
+			"caps": schema.SetNestedAttribute{
+				MarkdownDescription: "Admin capabilities of the user. Capabilities not configured here are removed from the user.",
+				Optional:            true,
+				Validators: []validator.Set{
+					userCapsValidator{},
+				},
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"type": schema.StringAttribute{
-							Required: true,
+							MarkdownDescription: "Type of the capability, e.g. `users`, `buckets`, `metadata`, `usage` or `zone`",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(userCapTypes...),
+							},
 						},
 						"perm": schema.StringAttribute{
-							Required: true,
+							MarkdownDescription: "Permission of the capability. Valid values are `read`, `write` and `*`.",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf("read", "write", "*"),
+							},
 						},
 					},
 				},
@@ -156,13 +176,24 @@ func (r *UserResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 				},
 			},
 			"secret_key": schema.StringAttribute{
-				MarkdownDescription: "The generated secret key",
+				MarkdownDescription: "The generated secret key. Not set if `pgp_key` is set.",
 				Computed:            true,
-				//Sensitive:           true,
+				Sensitive:           true,
 				PlanModifiers: []planmodifier.String{
 					stringPrivateUnknownModifier{"secret_key"},
 				},
 			},
+			"pgp_key": schema.StringAttribute{
+				MarkdownDescription: "ASCII armored or base64 encoded PGP public key. If set, the secret key is only saved encrypted in `encrypted_secret_key` instead of `secret_key`.",
+				Optional:            true,
+				Validators: []validator.String{
+					pgpKeyValidator{},
+				},
+			},
+			"encrypted_secret_key": schema.StringAttribute{
+				MarkdownDescription: "The generated secret key encrypted with `pgp_key` and base64 encoded, decrypt it with `base64 --decode | gpg --decrypt`.",
+				Computed:            true,
+			},
 			"purge_data_on_delete": schema.BoolAttribute{
 				MarkdownDescription: "Purge user data on deletion",
 				Optional:            true,
@@ -170,6 +201,9 @@ func (r *UserResource) Schema(ctx context.Context, req resource.SchemaRequest, r
 			"principal": schema.StringAttribute{
 				MarkdownDescription: "Computed principal to be used in policies",
 				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 		},
 	}
@@ -221,16 +255,6 @@ func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, r
 	}
 	rgwUser.GenerateKey = &generateKey
 
-	if len(data.Caps) > 0 {
-		rgwUser.Caps = make([]admin.UserCapSpec, len(data.Caps))
-		for i, c := range data.Caps {
-			rgwUser.Caps[i] = admin.UserCapSpec{
-				Type: c.Type.ValueString(),
-				Perm: c.Type.ValueString(),
-			}
-		}
-	}
-
 	maxBuckets := int(data.MaxBuckets.ValueInt64())
 	rgwUser.MaxBuckets = &maxBuckets
 
@@ -249,6 +273,11 @@ func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	// set resource id
 	data.Id = types.StringValue(createdUser.ID)
+
+	// add caps
+	if err := r.reconcileCaps(ctx, createdUser.ID, createdUser.Caps, data.Caps); err != nil {
+		resp.Diagnostics.AddError("could not set user caps", err.Error())
+	}
 	data.Principal = types.StringValue(fmt.Sprintf("arn:aws:iam::%s:user/%s", data.Tenant.ValueString(), data.Username.ValueString()))
 
 	// set access and secret key
@@ -265,6 +294,12 @@ func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, r
 		data.SecretKey = types.StringNull()
 	}
 
+	// encrypt secret key
+	data.SecretKey, data.EncryptedSecretKey, err = protectSecretKey(data.PGPKey, data.SecretKey, types.StringNull())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("pgp_key"), "could not encrypt secret key", err.Error())
+	}
+
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -277,6 +312,11 @@ func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	// check whether the resource has just been imported
+	imported, diags := req.Private.GetKey(ctx, "imported")
+	resp.Diagnostics.Append(diags...)
+	isImport := string(imported) == "1"
+
 	// prepare request attributes
 	reqUser := admin.User{
 		ID: data.Id.ValueString(),
@@ -310,9 +350,15 @@ func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		data.Tenant = types.StringNull()
 	}
 
+	// update principal
+	data.Principal = types.StringValue(fmt.Sprintf("arn:aws:iam::%s:user/%s", data.Tenant.ValueString(), data.Username.ValueString()))
+
 	// update display name
 	data.DisplayName = types.StringValue(user.DisplayName)
 
+	// update op_mask
+	data.OpMask = types.StringValue(user.OpMask)
+
 	// update email
 	if len(user.Email) > 0 || !data.Email.IsNull() {
 		data.Email = types.StringValue(user.Email)
@@ -329,7 +375,7 @@ func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp
 			data.Caps[i].Perm = types.StringValue(c.Perm)
 		}
 	} else {
-		user.Caps = nil
+		data.Caps = nil
 	}
 
 	// update max_buckets
@@ -349,7 +395,22 @@ func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	// update credentials
 	if data.GenerateS3Credentials.ValueBool() || data.GenerateS3Credentials.IsNull() {
 		found := false
-		if data.AccessKey.IsNull() || data.AccessKey.IsUnknown() {
+		if data.AccessKey.IsNull() && isImport {
+			// adopt the first s3 key pair of the user when importing
+			for _, k := range user.Keys {
+				if k.User == user.ID {
+					found = true
+					data.AccessKey = types.StringValue(k.AccessKey)
+					data.SecretKey = types.StringValue(k.SecretKey)
+					resp.Diagnostics.Append(resp.Private.SetKey(ctx, "mark_unknown_access_key", []byte("0"))...)
+					resp.Diagnostics.Append(resp.Private.SetKey(ctx, "mark_unknown_secret_key", []byte("0"))...)
+					break
+				}
+			}
+			if !found {
+				resp.Diagnostics.Append(resp.Private.SetKey(ctx, "mark_unknown_access_key", []byte("1"))...)
+			}
+		} else if data.AccessKey.IsNull() || data.AccessKey.IsUnknown() {
 			resp.Diagnostics.Append(resp.Private.SetKey(ctx, "mark_unknown_access_key", []byte("1"))...)
 		} else {
 			for _, k := range user.Keys {
@@ -375,6 +436,16 @@ func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		data.SecretKey = types.StringNull()
 	}
 
+	if isImport {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, "imported", []byte("0"))...)
+	}
+
+	// encrypt secret key
+	data.SecretKey, data.EncryptedSecretKey, err = protectSecretKey(data.PGPKey, data.SecretKey, data.EncryptedSecretKey)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("pgp_key"), "could not encrypt secret key", err.Error())
+	}
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -399,17 +470,6 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	generate := false
 	update.GenerateKey = &generate
 
-	// set user caps
-	if len(data.Caps) > 0 {
-		update.Caps = make([]admin.UserCapSpec, len(data.Caps))
-		for i, c := range data.Caps {
-			update.Caps[i] = admin.UserCapSpec{
-				Type: c.Type.ValueString(),
-				Perm: c.Type.ValueString(),
-			}
-		}
-	}
-
 	// set max_buckets
 	maxBuckets := int(data.MaxBuckets.ValueInt64())
 	update.MaxBuckets = &maxBuckets
@@ -428,6 +488,12 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		return
 	}
 
+	// update caps
+	if err := r.reconcileCaps(ctx, user.ID, user.Caps, data.Caps); err != nil {
+		resp.Diagnostics.AddError("could not update user caps", err.Error())
+		return
+	}
+
 	// manage s3 keys
 	tflog.Info(ctx, fmt.Sprintf("Access Key unknown: %t, Secret Key unknown: %t", data.AccessKey.IsUnknown(), data.SecretKey.IsUnknown()))
 	if data.SecretKey.IsUnknown() {
@@ -448,11 +514,12 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		if data.SecretKey.IsUnknown() {
 			tflog.Info(ctx, "Secret key still null")
 			if data.AccessKey.IsUnknown() {
-				a := make([]byte, 20)
-				for i := range a {
-					a[i] = accessKeyBytes[rand.Intn(len(accessKeyBytes))]
+				accessKey, err := randomString(accessKeyBytes, accessKeyLength)
+				if err != nil {
+					resp.Diagnostics.AddError("could not generate access key", err.Error())
+					return
 				}
-				data.AccessKey = types.StringValue(string(a))
+				data.AccessKey = types.StringValue(accessKey)
 			}
 
 			generate := true
@@ -486,6 +553,12 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		}
 	}
 
+	// encrypt secret key
+	data.SecretKey, data.EncryptedSecretKey, err = protectSecretKey(data.PGPKey, data.SecretKey, data.EncryptedSecretKey)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("pgp_key"), "could not encrypt secret key", err.Error())
+	}
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -513,6 +586,67 @@ func (r *UserResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	}
 }
 
+func (r *UserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// accept "user" and "tenant$user"
+	splittedId := strings.SplitN(req.ID, "$", 2)
+	if len(splittedId) == 2 && (splittedId[0] == "" || splittedId[1] == "") {
+		resp.Diagnostics.AddError("invalid import id", fmt.Sprintf("expected import id in the form 'user' or 'tenant$user', got '%s'", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, "imported", []byte("1"))...)
+}
+
+// reconcileCaps adds and removes caps of a user, so they match the configured caps.
+func (r *UserResource) reconcileCaps(ctx context.Context, uid string, current []admin.UserCapSpec, configured []UserCapModel) error {
+	perms := make(map[string]string, len(configured))
+	for _, c := range configured {
+		perms[c.Type.ValueString()] = c.Perm.ValueString()
+	}
+
+	// remove caps which are not configured or have another perm
+	existing := make(map[string]bool, len(current))
+	for _, c := range current {
+		if perm, ok := perms[c.Type]; ok && perm == c.Perm {
+			existing[c.Type] = true
+			continue
+		}
+		tflog.Debug(ctx, "removing user cap", map[string]interface{}{"uid": uid, "type": c.Type, "perm": c.Perm})
+		if _, err := r.client.Admin.RemoveUserCap(ctx, uid, fmt.Sprintf("%s=%s", c.Type, c.Perm)); err != nil {
+			return fmt.Errorf("could not remove cap '%s=%s': %w", c.Type, c.Perm, err)
+		}
+	}
+
+	// add missing caps
+	for _, c := range configured {
+		if existing[c.Type.ValueString()] {
+			continue
+		}
+		tflog.Debug(ctx, "adding user cap", map[string]interface{}{"uid": uid, "type": c.Type.ValueString(), "perm": c.Perm.ValueString()})
+		if _, err := r.client.Admin.AddUserCap(ctx, uid, fmt.Sprintf("%s=%s", c.Type.ValueString(), c.Perm.ValueString())); err != nil {
+			return fmt.Errorf("could not add cap '%s=%s': %w", c.Type.ValueString(), c.Perm.ValueString(), err)
+		}
+	}
+
+	return nil
+}
+
+// randomString returns a random string of n characters of the alphabet, e.g. a
+// key in the format generated by RGW.
+func randomString(alphabet string, n int) (string, error) {
+	a := make([]byte, n)
+	max := big.NewInt(int64(len(alphabet)))
+	for i := range a {
+		r, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		a[i] = alphabet[r.Int64()]
+	}
+	return string(a), nil
+}
+
 /*
 	type boolEnforceDefaultValueModifier struct {
 		Default bool
@@ -0,0 +1,282 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ceph/go-ceph/rgw/admin"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccUserResource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if fake.user("tenant$test") != nil {
+				return fmt.Errorf("user 'tenant$test' still exists")
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccUserResourceConfig("Test User"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_user.test", "id", "tenant$test"),
+					resource.TestCheckResourceAttr("rgw_user.test", "username", "test"),
+					resource.TestCheckResourceAttr("rgw_user.test", "tenant", "tenant"),
+					resource.TestCheckResourceAttr("rgw_user.test", "display_name", "Test User"),
+					resource.TestCheckResourceAttr("rgw_user.test", "max_buckets", "1000"),
+					resource.TestCheckResourceAttr("rgw_user.test", "suspended", "false"),
+					resource.TestCheckResourceAttr("rgw_user.test", "principal", "arn:aws:iam::tenant:user/test"),
+					resource.TestCheckResourceAttrSet("rgw_user.test", "access_key"),
+					resource.TestCheckResourceAttrSet("rgw_user.test", "secret_key"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_user.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update and Read testing
+			{
+				Config: fake.providerConfig() + testAccUserResourceConfig("Updated User"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_user.test", "display_name", "Updated User"),
+					func(s *terraform.State) error {
+						if name := fake.user("tenant$test").DisplayName; name != "Updated User" {
+							return fmt.Errorf("expected display name 'Updated User', got '%s'", name)
+						}
+						return nil
+					},
+				),
+			},
+			// Drift testing, changes outside of terraform are reverted
+			{
+				PreConfig: func() {
+					fake.modifyFakeUser("tenant$test", func(user *admin.User) {
+						user.DisplayName = "Changed User"
+					})
+				},
+				Config: fake.providerConfig() + testAccUserResourceConfig("Updated User"),
+				Check: func(s *terraform.State) error {
+					if name := fake.user("tenant$test").DisplayName; name != "Updated User" {
+						return fmt.Errorf("expected display name 'Updated User', got '%s'", name)
+					}
+					return nil
+				},
+			},
+			// Drift testing, users deleted outside of terraform are recreated
+			{
+				PreConfig: func() {
+					fake.deleteUser("tenant$test")
+				},
+				Config: fake.providerConfig() + testAccUserResourceConfig("Updated User"),
+				Check: func(s *terraform.State) error {
+					if fake.user("tenant$test") == nil {
+						return fmt.Errorf("user 'tenant$test' was not recreated")
+					}
+					return nil
+				},
+			},
+			// Delete testing automatically occurs in TestCase
+		},
+	})
+}
+
+func testAccUserResourceConfig(displayName string) string {
+	return fmt.Sprintf(`
+resource "rgw_user" "test" {
+  username     = "test"
+  tenant       = "tenant"
+  display_name = %[1]q
+}
+`, displayName)
+}
+
+func TestAccUserResource_caps(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: fake.providerConfig() + testAccUserResourceCapsConfig(map[string]string{"users": "read", "buckets": "*"}),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_user.test", "caps.#", "2"),
+					resource.TestCheckTypeSetElemNestedAttrs("rgw_user.test", "caps.*", map[string]string{"type": "users", "perm": "read"}),
+					resource.TestCheckTypeSetElemNestedAttrs("rgw_user.test", "caps.*", map[string]string{"type": "buckets", "perm": "*"}),
+					testAccCheckUserCaps(fake, "test", "buckets=*;users=read"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "rgw_user.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update testing, changed perms are replaced and removed caps deleted
+			{
+				Config: fake.providerConfig() + testAccUserResourceCapsConfig(map[string]string{"users": "*"}),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("rgw_user.test", "caps.#", "1"),
+					testAccCheckUserCaps(fake, "test", "users=*"),
+				),
+			},
+			// Drift testing, caps added outside of terraform are removed
+			{
+				PreConfig: func() {
+					fake.modifyFakeUser("test", func(user *admin.User) {
+						user.Caps = append(user.Caps, admin.UserCapSpec{Type: "zone", Perm: "read"})
+					})
+				},
+				Config: fake.providerConfig() + testAccUserResourceCapsConfig(map[string]string{"users": "*"}),
+				Check:  testAccCheckUserCaps(fake, "test", "users=*"),
+			},
+			// Remove all caps
+			{
+				Config: fake.providerConfig() + testAccUserResourceCapsConfig(nil),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("rgw_user.test", "caps.#"),
+					testAccCheckUserCaps(fake, "test", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccUserResource_invalidCaps(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      fake.providerConfig() + testAccUserResourceCapsConfig(map[string]string{"user": "read"}),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Match`),
+			},
+			{
+				Config:      fake.providerConfig() + testAccUserResourceCapsConfig(map[string]string{"users": "readwrite"}),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Match`),
+			},
+			{
+				Config: fake.providerConfig() + `
+resource "rgw_user" "test" {
+  username     = "test"
+  display_name = "Test User"
+
+  caps = [
+    { type = "users", perm = "read" },
+    { type = "users", perm = "write" },
+  ]
+}
+`,
+				ExpectError: regexp.MustCompile(`cap type 'users' is configured more than once`),
+			},
+		},
+	})
+}
+
+func TestAccUserResource_pgpKey(t *testing.T) {
+	fake := newFakeRgw(t)
+	entity, armored, encoded := testPGPKey(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create testing, the secret key is only saved encrypted
+			{
+				Config: fake.providerConfig() + testAccUserResourcePGPKeyConfig(fmt.Sprintf("<<EOT\n%sEOT", armored)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("rgw_user.test", "access_key"),
+					resource.TestCheckNoResourceAttr("rgw_user.test", "secret_key"),
+					testAccCheckEncryptedSecretKey(fake, entity, "test", "rgw_user.test"),
+				),
+			},
+			// Refresh testing, the encrypted secret key is stable
+			{
+				Config:   fake.providerConfig() + testAccUserResourcePGPKeyConfig(fmt.Sprintf("<<EOT\n%sEOT", armored)),
+				PlanOnly: true,
+			},
+			// Update testing, a changed key encrypts the secret key again
+			{
+				Config: fake.providerConfig() + testAccUserResourcePGPKeyConfig(fmt.Sprintf("%q", encoded)),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("rgw_user.test", "secret_key"),
+					testAccCheckEncryptedSecretKey(fake, entity, "test", "rgw_user.test"),
+				),
+			},
+			// Removing the key saves the plain secret key again
+			{
+				Config: fake.providerConfig() + testAccUserConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("rgw_user.test", "secret_key"),
+					resource.TestCheckNoResourceAttr("rgw_user.test", "encrypted_secret_key"),
+				),
+			},
+		},
+	})
+}
+
+func testAccUserResourcePGPKeyConfig(pgpKey string) string {
+	return fmt.Sprintf(`
+resource "rgw_user" "test" {
+  username     = "test"
+  display_name = "Test User"
+  pgp_key      = %s
+}
+`, pgpKey)
+}
+
+func testAccUserResourceCapsConfig(caps map[string]string) string {
+	if len(caps) == 0 {
+		return testAccUserConfig
+	}
+
+	var entries []string
+	for capType, perm := range caps {
+		entries = append(entries, fmt.Sprintf("    { type = %q, perm = %q },", capType, perm))
+	}
+	sort.Strings(entries)
+
+	return fmt.Sprintf(`
+resource "rgw_user" "test" {
+  username     = "test"
+  display_name = "Test User"
+
+  caps = [
+%s
+  ]
+}
+`, strings.Join(entries, "\n"))
+}
+
+// testAccCheckUserCaps compares the caps of the user in the fake with the sorted
+// caps in the format "type=perm;type=perm".
+func testAccCheckUserCaps(fake *fakeRgw, uid string, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		user := fake.user(uid)
+		if user == nil {
+			return fmt.Errorf("user '%s' does not exist", uid)
+		}
+
+		caps := make([]string, len(user.Caps))
+		for i, c := range user.Caps {
+			caps[i] = fmt.Sprintf("%s=%s", c.Type, c.Perm)
+		}
+		sort.Strings(caps)
+
+		if actual := strings.Join(caps, ";"); actual != expected {
+			return fmt.Errorf("expected caps '%s', got '%s'", expected, actual)
+		}
+		return nil
+	}
+}
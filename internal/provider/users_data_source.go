@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSourceWithConfigure = &UsersDataSource{}
+
+func NewUsersDataSource() datasource.DataSource {
+	return &UsersDataSource{}
+}
+
+type UsersDataSource struct {
+	client *RgwClient
+}
+
+type UsersDataSourceModel struct {
+	Id     types.String `tfsdk:"id"`
+	Tenant types.String `tfsdk:"tenant"`
+	Prefix types.String `tfsdk:"prefix"`
+	Ids    []string     `tfsdk:"ids"`
+}
+
+func (d *UsersDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_users"
+}
+
+func (d *UsersDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "List of Ceph RGW Users",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"tenant": schema.StringAttribute{
+				MarkdownDescription: "Only list users of this tenant. Set to an empty string to only list users without tenant.",
+				Optional:            true,
+			},
+			"prefix": schema.StringAttribute{
+				MarkdownDescription: "Only list users whose user ID (without tenant) starts with this prefix",
+				Optional:            true,
+			},
+			"ids": schema.ListAttribute{
+				MarkdownDescription: "Sorted user IDs including the tenant (`tenant$user`)",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *UsersDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*RgwClient)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *RgwClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *UsersDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// Read Terraform configuration data into the model
+	var data *UsersDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// list users
+	users, err := d.client.Admin.GetUsers(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("could not list users", err.Error())
+		return
+	}
+
+	data.Ids = make([]string, 0)
+	if users != nil {
+		for _, id := range *users {
+			tenant, username := "", id
+			if splittedId := strings.SplitN(id, "$", 2); len(splittedId) == 2 {
+				tenant, username = splittedId[0], splittedId[1]
+			}
+			if !data.Tenant.IsNull() && tenant != data.Tenant.ValueString() {
+				continue
+			}
+			if !strings.HasPrefix(username, data.Prefix.ValueString()) {
+				continue
+			}
+			data.Ids = append(data.Ids, id)
+		}
+	}
+	sort.Strings(data.Ids)
+
+	data.Id = types.StringValue(fmt.Sprintf("%s$%s", data.Tenant.ValueString(), data.Prefix.ValueString()))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccUsersDataSource(t *testing.T) {
+	fake := newFakeRgw(t)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: fake.providerConfig() + testAccUsersDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.rgw_users.test", "ids.#", "2"),
+					resource.TestCheckResourceAttr("data.rgw_users.test", "ids.0", "tenant$test-a"),
+					resource.TestCheckResourceAttr("data.rgw_users.test", "ids.1", "tenant$test-b"),
+				),
+			},
+		},
+	})
+}
+
+var testAccUsersDataSourceConfig = testAccNamedUserConfig("test-a", "tenant", "test-a") +
+	testAccNamedUserConfig("test-b", "tenant", "test-b") +
+	testAccNamedUserConfig("other", "tenant", "other") + `
+data "rgw_users" "test" {
+  tenant = "tenant"
+  prefix = "test-"
+
+  depends_on = [rgw_user.test_a, rgw_user.test_b, rgw_user.other]
+}
+`
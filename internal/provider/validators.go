@@ -0,0 +1,366 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+type rfc3339Validator struct{}
+
+func (v rfc3339Validator) Description(ctx context.Context) string {
+	return "value must be a date in RFC3339 format"
+}
+
+func (v rfc3339Validator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a date in RFC3339 format, e.g. `2023-01-01T00:00:00Z`"
+}
+
+func (v rfc3339Validator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := time.Parse(time.RFC3339, req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "invalid date", err.Error())
+	}
+}
+
+type durationValidator struct{}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return "value must be a positive duration"
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a positive duration, e.g. `30s` or `5m`"
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	d, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "invalid duration", err.Error())
+		return
+	}
+	if d <= 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "invalid duration", "duration must be positive")
+	}
+}
+
+type pgpKeyValidator struct{}
+
+func (v pgpKeyValidator) Description(ctx context.Context) string {
+	return "value must be an ASCII armored or base64 encoded PGP public key"
+}
+
+func (v pgpKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be an ASCII armored or base64 encoded PGP public key"
+}
+
+func (v pgpKeyValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if _, err := readPGPPublicKey(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "invalid pgp key", err.Error())
+	}
+}
+
+type topicAttributesValidator struct{}
+
+func (v topicAttributesValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("attributes must not contain %s, which are set by dedicated attributes", strings.Join(reservedTopicAttributes, ", "))
+}
+
+func (v topicAttributesValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("attributes must not contain `%s`, which are set by dedicated attributes", strings.Join(reservedTopicAttributes, "`, `"))
+}
+
+func (v topicAttributesValidator) ValidateMap(ctx context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	for key := range req.ConfigValue.Elements() {
+		for _, reserved := range reservedTopicAttributes {
+			if strings.EqualFold(key, reserved) {
+				resp.Diagnostics.AddAttributeError(req.Path, "reserved topic attribute", fmt.Sprintf("attribute '%s' must be set by the dedicated attribute of the topic", key))
+			}
+		}
+	}
+}
+
+// cap types known to RGW.
+var userCapTypes = []string{
+	"amz-cache",
+	"bilog",
+	"buckets",
+	"datalog",
+	"info",
+	"mdlog",
+	"metadata",
+	"oidc-provider",
+	"ratelimit",
+	"roles",
+	"usage",
+	"user-info-without-keys",
+	"user-policy",
+	"users",
+	"zone",
+}
+
+type userCapsValidator struct{}
+
+func (v userCapsValidator) Description(ctx context.Context) string {
+	return "each cap type must only be configured once"
+}
+
+func (v userCapsValidator) MarkdownDescription(ctx context.Context) string {
+	return "each cap `type` must only be configured once"
+}
+
+func (v userCapsValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	var caps []UserCapModel
+	resp.Diagnostics.Append(req.ConfigValue.ElementsAs(ctx, &caps, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	seen := make(map[string]bool, len(caps))
+	for _, c := range caps {
+		if c.Type.IsUnknown() {
+			continue
+		}
+		if seen[c.Type.ValueString()] {
+			resp.Diagnostics.AddAttributeError(req.Path, "duplicate cap type", fmt.Sprintf("cap type '%s' is configured more than once, use perm '*' to grant read and write", c.Type.ValueString()))
+			continue
+		}
+		seen[c.Type.ValueString()] = true
+	}
+}
+
+type policyDocumentValidator struct{}
+
+func (v policyDocumentValidator) Description(ctx context.Context) string {
+	return "value must be a valid bucket policy document in JSON format"
+}
+
+func (v policyDocumentValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a valid bucket policy document in JSON format, with `s3:` actions supported by Ceph RGW"
+}
+
+func (v policyDocumentValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	policy := req.ConfigValue.ValueString()
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			line, column := jsonPosition(policy, syntaxErr.Offset)
+			resp.Diagnostics.AddAttributeError(req.Path, "invalid policy document", fmt.Sprintf("invalid JSON at line %d, column %d: %s", line, column, err.Error()))
+			return
+		}
+		resp.Diagnostics.AddAttributeError(req.Path, "invalid policy document", fmt.Sprintf("policy must be a JSON object: %s", err.Error()))
+		return
+	}
+
+	// statements may be a single object or a list of objects
+	var statements []interface{}
+	switch s := doc["Statement"].(type) {
+	case []interface{}:
+		statements = s
+	case map[string]interface{}:
+		statements = []interface{}{s}
+	default:
+		resp.Diagnostics.AddAttributeError(req.Path, "invalid policy document", "policy must contain a 'Statement' object or a list of 'Statement' objects")
+		return
+	}
+
+	for i, s := range statements {
+		statement, ok := s.(map[string]interface{})
+		name := fmt.Sprintf("Statement[%d]", i)
+		if !ok {
+			resp.Diagnostics.AddAttributeError(req.Path, "invalid policy document", fmt.Sprintf("%s must be an object", name))
+			continue
+		}
+		if sid, ok := statement["Sid"].(string); ok && sid != "" {
+			name = fmt.Sprintf("Statement[%d] (Sid %q)", i, sid)
+		}
+
+		if effect, _ := statement["Effect"].(string); effect != "Allow" && effect != "Deny" {
+			resp.Diagnostics.AddAttributeError(req.Path, "invalid policy document", fmt.Sprintf("%s: 'Effect' must be 'Allow' or 'Deny'", name))
+		}
+
+		_, hasAction := statement["Action"]
+		_, hasNotAction := statement["NotAction"]
+		if !hasAction && !hasNotAction {
+			resp.Diagnostics.AddAttributeError(req.Path, "invalid policy document", fmt.Sprintf("%s must contain 'Action' or 'NotAction'", name))
+		}
+
+		for _, key := range []string{"Action", "NotAction"} {
+			value, ok := statement[key]
+			if !ok {
+				continue
+			}
+			actions, ok := policyStringList(value)
+			if !ok {
+				resp.Diagnostics.AddAttributeError(req.Path, "invalid policy document", fmt.Sprintf("%s: '%s' must be a string or a list of strings", name, key))
+				continue
+			}
+			for _, action := range actions {
+				if !isKnownS3Action(action) {
+					resp.Diagnostics.AddAttributeError(req.Path, "invalid policy document", fmt.Sprintf("%s: unknown action '%s' in '%s'", name, action, key))
+				}
+			}
+		}
+	}
+}
+
+// policyStringList returns the values of a policy element that is either a
+// single string or a list of strings.
+func policyStringList(v interface{}) ([]string, bool) {
+	switch t := v.(type) {
+	case string:
+		return []string{t}, true
+	case []interface{}:
+		res := make([]string, 0, len(t))
+		for _, e := range t {
+			s, ok := e.(string)
+			if !ok {
+				return nil, false
+			}
+			res = append(res, s)
+		}
+		return res, true
+	}
+	return nil, false
+}
+
+// jsonPosition converts a byte offset into a line and column number.
+func jsonPosition(s string, offset int64) (int, int) {
+	if offset > int64(len(s)) {
+		offset = int64(len(s))
+	}
+	before := s[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := len(before) - strings.LastIndex(before, "\n")
+	return line, column
+}
+
+// s3 actions supported in bucket policies by Ceph RGW.
+var s3PolicyActions = []string{
+	"AbortMultipartUpload",
+	"BypassGovernanceRetention",
+	"CreateBucket",
+	"DeleteBucket",
+	"DeleteBucketPolicy",
+	"DeleteBucketWebsite",
+	"DeleteObject",
+	"DeleteObjectTagging",
+	"DeleteObjectVersion",
+	"DeleteObjectVersionTagging",
+	"DeleteReplicationConfiguration",
+	"GetAccelerateConfiguration",
+	"GetBucketAcl",
+	"GetBucketCORS",
+	"GetBucketEncryption",
+	"GetBucketLocation",
+	"GetBucketLogging",
+	"GetBucketNotification",
+	"GetBucketObjectLockConfiguration",
+	"GetBucketPolicy",
+	"GetBucketPolicyStatus",
+	"GetBucketPublicAccessBlock",
+	"GetBucketRequestPayment",
+	"GetBucketTagging",
+	"GetBucketVersioning",
+	"GetBucketWebsite",
+	"GetLifecycleConfiguration",
+	"GetObject",
+	"GetObjectAcl",
+	"GetObjectLegalHold",
+	"GetObjectRetention",
+	"GetObjectTagging",
+	"GetObjectTorrent",
+	"GetObjectVersion",
+	"GetObjectVersionAcl",
+	"GetObjectVersionForReplication",
+	"GetObjectVersionTagging",
+	"GetObjectVersionTorrent",
+	"GetPublicAccessBlock",
+	"GetReplicationConfiguration",
+	"ListAllMyBuckets",
+	"ListBucket",
+	"ListBucketMultipartUploads",
+	"ListBucketVersions",
+	"ListMultipartUploadParts",
+	"PutAccelerateConfiguration",
+	"PutBucketAcl",
+	"PutBucketCORS",
+	"PutBucketEncryption",
+	"PutBucketLogging",
+	"PutBucketNotification",
+	"PutBucketObjectLockConfiguration",
+	"PutBucketPolicy",
+	"PutBucketPublicAccessBlock",
+	"PutBucketRequestPayment",
+	"PutBucketTagging",
+	"PutBucketVersioning",
+	"PutBucketWebsite",
+	"PutLifecycleConfiguration",
+	"PutObject",
+	"PutObjectAcl",
+	"PutObjectLegalHold",
+	"PutObjectRetention",
+	"PutObjectTagging",
+	"PutObjectVersionAcl",
+	"PutObjectVersionTagging",
+	"PutPublicAccessBlock",
+	"PutReplicationConfiguration",
+	"RestoreObject",
+}
+
+// isKnownS3Action checks if an action matches at least one supported s3 action.
+// Actions are case insensitive and may contain the wildcards '*' and '?'.
+func isKnownS3Action(action string) bool {
+	if action == "*" {
+		return true
+	}
+	if len(action) < 3 || !strings.EqualFold(action[:3], "s3:") {
+		return false
+	}
+
+	pattern := regexp.QuoteMeta(action[3:])
+	pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+	pattern = strings.ReplaceAll(pattern, `\?`, ".")
+	re, err := regexp.Compile("(?i)^" + pattern + "$")
+	if err != nil {
+		return false
+	}
+
+	for _, a := range s3PolicyActions {
+		if re.MatchString(a) {
+			return true
+		}
+	}
+	return false
+}